@@ -0,0 +1,125 @@
+package grbtree
+
+// BestFit 中的空闲块，保存在按地址排序的树中
+type fitBlock struct {
+	size     RBTreeKey
+	sizeNode *RBTreeNode // 按大小排序的树中的节点，节点的值为地址树中的节点
+}
+
+// 最佳适配分配器，从空闲块中选择不小于请求大小的最小块
+// 空闲块同时保存在两棵树中：
+// 按大小排序的多值树用于 Ceiling 查找最佳的块，按地址排序的树用于释放时合并相邻的空闲块
+//
+//	a := grbtree.NewBestFit(0, 1<<20)
+//	addr, err := a.Allocate(64)
+//	a.Free(addr)
+type BestFit struct {
+	bySize *RBTree
+	byAddr *RBTree
+	used   map[int64]int64 // 已分配的地址和大小
+	free   int64
+}
+
+// 管理从 base 开始大小为 size 的空间
+func NewBestFit(base, size int64) *BestFit {
+	a := &BestFit{
+		bySize: NewMultiRBTree(),
+		byAddr: NewRBTree(),
+		used:   make(map[int64]int64),
+	}
+	if size > 0 {
+		a.addFree(RBTreeKey(base), RBTreeKey(size))
+		a.free = size
+	}
+	return a
+}
+
+// 添加空闲块
+func (a *BestFit) addFree(addr, size RBTreeKey) {
+	an := a.byAddr.newNode(addr, nil)
+	a.byAddr.addNode(an)
+	a.setSize(an, size)
+}
+
+// 修改地址树中空闲块的大小，同时更新大小树
+func (a *BestFit) setSize(an *RBTreeNode, size RBTreeKey) {
+	b, _ := an.value.(*fitBlock)
+	if b == nil {
+		b = &fitBlock{}
+		an.value = b
+	} else {
+		a.bySize.delete(b.sizeNode)
+	}
+	b.size = size
+	b.sizeNode = a.bySize.newNode(size, an)
+	a.bySize.addNode(b.sizeNode)
+}
+
+// 删除空闲块
+func (a *BestFit) removeFree(an *RBTreeNode) {
+	a.bySize.delete(an.value.(*fitBlock).sizeNode)
+	a.byAddr.delete(an)
+}
+
+// 分配 size 大小的空间，返回地址；空闲块比请求的大时拆分，剩余部分仍为空闲块
+func (a *BestFit) Allocate(size int64) (int64, error) {
+	if size <= 0 {
+		return 0, errNoFreeBlock
+	}
+	sn := a.bySize.lowerBound(RBTreeKey(size))
+	if sn == nil {
+		return 0, errNoFreeBlock
+	}
+	an := sn.value.(*RBTreeNode)
+	addr, b := an.key, an.value.(*fitBlock)
+	if b.size == RBTreeKey(size) {
+		a.removeFree(an)
+	} else {
+		// 剩余部分的地址仍在原先的块内，修改 key 不改变地址树的顺序
+		an.key = addr + RBTreeKey(size)
+		a.setSize(an, b.size-RBTreeKey(size))
+	}
+	a.used[int64(addr)] = size
+	a.free -= size
+	return int64(addr), nil
+}
+
+// 释放 Allocate 返回的地址，与前后相邻的空闲块合并
+func (a *BestFit) Free(addr int64) error {
+	size, ok := a.used[addr]
+	if !ok {
+		return errNotAllocated
+	}
+	delete(a.used, addr)
+	a.free += size
+	start, end := RBTreeKey(addr), RBTreeKey(addr+size)
+	if next := a.byAddr.lowerBound(start); next != nil && next.key == end {
+		end += next.value.(*fitBlock).size
+		a.removeFree(next)
+	}
+	if prev := a.byAddr.floorNode(start); prev != nil && prev.key+prev.value.(*fitBlock).size == start {
+		a.setSize(prev, end-prev.key)
+		return nil
+	}
+	a.addFree(start, end-start)
+	return nil
+}
+
+// 空闲空间的总大小
+func (a *BestFit) FreeBytes() int64 {
+	return a.free
+}
+
+// 最大的空闲块大小
+func (a *BestFit) LargestFree() int64 {
+	k, _, err := a.bySize.GetMax()
+	if err != nil {
+		return 0
+	}
+	return int64(k)
+}
+
+// 空闲块的数量
+func (a *BestFit) FreeBlocks() int {
+	return a.byAddr.Len()
+}
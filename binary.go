@@ -0,0 +1,130 @@
+package grbtree
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"math/bits"
+)
+
+// 二进制格式:
+//
+//	magic "GRBT" | version(1 byte) | 节点数量(uvarint)
+//	节点按顺序排列: key(varint) | 值长度(uvarint) | 值
+//	crc32(IEEE, 4 bytes 大端) 校验之前的所有数据
+const (
+	binaryMagic   = "GRBT"
+	binaryVersion = 1
+)
+
+// 实现 encoding.BinaryMarshaler，值使用 WithValueCodec 设置的编解码
+func (t *RBTree) MarshalBinary() ([]byte, error) {
+	codec := t.valueCodec()
+	b := make([]byte, 0, len(binaryMagic)+1+binary.MaxVarintLen64+int(t.length)*4)
+	b = append(b, binaryMagic...)
+	b = append(b, binaryVersion)
+	b = appendUvarint(b, uint64(t.length))
+	for n := t.minNode; n != nil; n = n.Next() {
+		v, err := codec.EncodeValue(n.value)
+		if err != nil {
+			return nil, err
+		}
+		b = appendVarint(b, int64(n.key))
+		b = appendUvarint(b, uint64(len(v)))
+		b = append(b, v...)
+	}
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(b))
+	return append(b, sum[:]...), nil
+}
+
+// 实现 encoding.BinaryUnmarshaler，树原有的节点将被清除
+// 数据中的节点已经有序，直接以 O(n) 构建平衡的树
+func (t *RBTree) UnmarshalBinary(data []byte) error {
+	if len(data) < len(binaryMagic)+1+4 || string(data[:len(binaryMagic)]) != binaryMagic {
+		return errBadFormat
+	}
+	if data[len(binaryMagic)] != binaryVersion {
+		return errBadVersion
+	}
+	body, sum := data[:len(data)-4], binary.BigEndian.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(body) != sum {
+		return errBadChecksum
+	}
+	b := body[len(binaryMagic)+1:]
+	count, l := binary.Uvarint(b)
+	if l <= 0 || count > uint64(len(b)) {
+		return errBadFormat
+	}
+	b = b[l:]
+	codec := t.valueCodec()
+	nodes := make([]*RBTreeNode, 0, count)
+	for i := uint64(0); i < count; i++ {
+		k, l := binary.Varint(b)
+		if l <= 0 {
+			return errBadFormat
+		}
+		b = b[l:]
+		vl, l := binary.Uvarint(b)
+		if l <= 0 || vl > uint64(len(b)-l) {
+			return errBadFormat
+		}
+		v, err := codec.DecodeValue(b[l : l+int(vl)])
+		if err != nil {
+			return err
+		}
+		b = b[l+int(vl):]
+		nodes = append(nodes, &RBTreeNode{key: RBTreeKey(k), value: v})
+	}
+	if len(b) != 0 {
+		return errBadFormat
+	}
+	return t.buildSorted(nodes)
+}
+
+// 用有序的节点构建树，替换树原有的节点
+func (t *RBTree) buildSorted(nodes []*RBTreeNode) error {
+	for i := 1; i < len(nodes); i++ {
+		c := t.compare(nodes[i-1].key, nodes[i].key)
+		if c > 0 || c == 0 && t.dup != DuplicateAllow {
+			return errUnsorted
+		}
+	}
+	t.Clear()
+	if len(nodes) == 0 {
+		return nil
+	}
+	// 每次取中间的节点作为根节点，得到的树所有 nil 节点的深度相差不超过 1，
+	// 把最深一层的节点设为红色，即可满足红黑树的性质
+	redDepth := bits.Len(uint(len(nodes))) - 1
+	t.root = buildBalanced(nodes, nil, 0, redDepth)
+	t.root.color = BLACK
+	t.length = uint32(len(nodes))
+	t.modCount++
+	t.minNode = nodes[0]
+	t.maxNode = nodes[len(nodes)-1]
+	return nil
+}
+
+func buildBalanced(nodes []*RBTreeNode, parent *RBTreeNode, depth, redDepth int) *RBTreeNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+	mid := len(nodes) / 2
+	n := nodes[mid]
+	n.parent = parent
+	n.left = buildBalanced(nodes[:mid], n, depth+1, redDepth)
+	n.right = buildBalanced(nodes[mid+1:], n, depth+1, redDepth)
+	n.color = depth == redDepth
+	n.size = 1 + n.left.subSize() + n.right.subSize()
+	return n
+}
+
+func appendUvarint(b []byte, x uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], x)]...)
+}
+
+func appendVarint(b []byte, x int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], x)]...)
+}
@@ -0,0 +1,84 @@
+package grbtree
+
+// 超出容量时淘汰的节点
+type EvictPolicy int
+
+const (
+	EvictMin EvictPolicy = iota // 淘汰最小节点，保留最大的 N 个节点
+	EvictMax                    // 淘汰最大节点，保留最小的 N 个节点
+)
+
+// 有容量上限的树，Add 后节点数量超过容量时按策略淘汰最小或最大节点
+//
+//	// 保留最大的 10 个
+//	bt := grbtree.NewBoundedTree(10, grbtree.EvictMin, nil)
+type BoundedTree struct {
+	t        *RBTree
+	capacity int
+	policy   EvictPolicy
+	onEvict  func(k RBTreeKey, v interface{})
+}
+
+// onEvict 在节点被淘汰时调用，可以为 nil；opts 为创建树的配置项
+// capacity 小于 0 时视为 0
+func NewBoundedTree(capacity int, policy EvictPolicy, onEvict func(k RBTreeKey, v interface{}), opts ...Option) *BoundedTree {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &BoundedTree{
+		t:        NewRBTree(opts...),
+		capacity: capacity,
+		policy:   policy,
+		onEvict:  onEvict,
+	}
+}
+
+// 添加节点，超过容量时淘汰节点，新添加的节点也可能被淘汰
+func (b *BoundedTree) Add(k int, v interface{}) {
+	b.t.Add(k, v)
+	for b.t.Len() > b.capacity {
+		var ek RBTreeKey
+		var ev interface{}
+		var err error
+		if b.policy == EvictMin {
+			ek, ev, err = b.t.PopMin()
+		} else {
+			ek, ev, err = b.t.PopMax()
+		}
+		if err != nil {
+			break
+		}
+		if b.onEvict != nil {
+			b.onEvict(ek, ev)
+		}
+	}
+}
+
+func (b *BoundedTree) Get(k int) (interface{}, error) {
+	return b.t.Get(k)
+}
+
+func (b *BoundedTree) Del(k int) {
+	b.t.Del(k)
+}
+
+func (b *BoundedTree) GetMin() (RBTreeKey, interface{}, error) {
+	return b.t.GetMin()
+}
+
+func (b *BoundedTree) GetMax() (RBTreeKey, interface{}, error) {
+	return b.t.GetMax()
+}
+
+func (b *BoundedTree) Len() int {
+	return b.t.Len()
+}
+
+func (b *BoundedTree) Capacity() int {
+	return b.capacity
+}
+
+// 按顺序遍历的游标
+func (b *BoundedTree) Cursor() *Cursor {
+	return b.t.Cursor()
+}
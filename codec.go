@@ -0,0 +1,45 @@
+package grbtree
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// 序列化时节点值的编解码
+type ValueCodec interface {
+	EncodeValue(v interface{}) ([]byte, error)
+	DecodeValue(b []byte) (interface{}, error)
+}
+
+// 使用 encoding/gob 编解码值，自定义类型需要先 gob.Register
+// nil 编码为空字节
+type GobCodec struct{}
+
+func (GobCodec) EncodeValue(v interface{}) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	var b bytes.Buffer
+	if err := gob.NewEncoder(&b).Encode(&v); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+func (GobCodec) DecodeValue(b []byte) (interface{}, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	var v interface{}
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (t *RBTree) valueCodec() ValueCodec {
+	if t.codec == nil {
+		return GobCodec{}
+	}
+	return t.codec
+}
@@ -0,0 +1,326 @@
+package grbtree
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// 不可变的节点，修改时复制路径上的节点
+type cowNode struct {
+	key   RBTreeKey
+	value interface{}
+	color bool
+	left  *cowNode
+	right *cowNode
+}
+
+// COWTree 的一个版本，发布后不再修改
+type cowVersion struct {
+	root    *cowNode
+	length  int
+	minNode *cowNode
+	maxNode *cowNode
+}
+
+// 写时复制的红黑树，读操作不加锁，写操作串行执行
+// 写操作复制从根节点到修改节点路径上的节点生成新版本，通过 atomic.Pointer 发布，
+// 读操作读取当时的版本，不会被写操作阻塞
+//
+//	tree := grbtree.NewCOWTree()
+//	tree.Add(1, "a")
+//	v, err := tree.Get(1)
+//
+// 不支持多值模式，添加已存在的 key 时默认忽略新值，WithCOWReplace 时替换原先的值
+type COWTree struct {
+	mu      sync.Mutex // 串行化写操作
+	version atomic.Pointer[cowVersion]
+	cmp     func(a, b RBTreeKey) int
+	replace bool
+}
+
+// NewCOWTree 的配置项
+// NewRBTree 的其他配置项(多值模式、预分配节点、值的编解码等)对 COWTree 没有意义，因此使用单独的类型
+type COWOption func(c *COWTree)
+
+// 自定义 key 的比较函数，与 WithComparator 相同
+func WithCOWComparator(cmp func(a, b RBTreeKey) int) COWOption {
+	return func(c *COWTree) {
+		c.cmp = cmp
+	}
+}
+
+// 添加已存在的 key 时用新值替换原先的值，默认忽略新值
+func WithCOWReplace(on bool) COWOption {
+	return func(c *COWTree) {
+		c.replace = on
+	}
+}
+
+func NewCOWTree(opts ...COWOption) *COWTree {
+	c := &COWTree{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.cmp == nil {
+		c.cmp = compareKey
+	}
+	c.version.Store(&cowVersion{})
+	return c
+}
+
+func (n *cowNode) isRed() bool {
+	return n != nil && n.color == RED
+}
+
+func newCOWNode(color bool, left *cowNode, key RBTreeKey, value interface{}, right *cowNode) *cowNode {
+	return &cowNode{key: key, value: value, color: color, left: left, right: right}
+}
+
+// 复制节点并修改颜色
+func (n *cowNode) withColor(color bool) *cowNode {
+	return newCOWNode(color, n.left, n.key, n.value, n.right)
+}
+
+func (c *COWTree) Get(k int) (interface{}, error) {
+	n := c.version.Load().root
+	for n != nil {
+		r := c.cmp(RBTreeKey(k), n.key)
+		if r < 0 {
+			n = n.left
+		} else if r > 0 {
+			n = n.right
+		} else {
+			return n.value, nil
+		}
+	}
+	return nil, errKeyNotExists
+}
+
+func (c *COWTree) GetMin() (RBTreeKey, interface{}, error) {
+	v := c.version.Load()
+	if v.minNode == nil {
+		return 0, nil, errNotNode
+	}
+	return v.minNode.key, v.minNode.value, nil
+}
+
+func (c *COWTree) GetMax() (RBTreeKey, interface{}, error) {
+	v := c.version.Load()
+	if v.maxNode == nil {
+		return 0, nil, errNotNode
+	}
+	return v.maxNode.key, v.maxNode.value, nil
+}
+
+func (c *COWTree) Len() int {
+	return c.version.Load().length
+}
+
+// 按顺序遍历 [lo, hi] 范围内的节点，fn 返回 false 时停止遍历
+// 遍历的是调用时的版本，不受遍历过程中写操作的影响
+func (c *COWTree) Range(lo, hi int, fn func(k RBTreeKey, v interface{}) bool) {
+	c.rangeNode(c.version.Load().root, RBTreeKey(lo), RBTreeKey(hi), fn)
+}
+
+func (c *COWTree) rangeNode(n *cowNode, lo, hi RBTreeKey, fn func(k RBTreeKey, v interface{}) bool) bool {
+	if n == nil {
+		return true
+	}
+	geLo, leHi := c.cmp(n.key, lo) >= 0, c.cmp(n.key, hi) <= 0
+	if geLo && !c.rangeNode(n.left, lo, hi, fn) {
+		return false
+	}
+	if geLo && leHi && !fn(n.key, n.value) {
+		return false
+	}
+	if leHi {
+		return c.rangeNode(n.right, lo, hi, fn)
+	}
+	return true
+}
+
+// 添加节点，生成并发布新版本
+func (c *COWTree) Add(k int, v interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old := c.version.Load()
+	root, added := c.insert(old.root, RBTreeKey(k), v)
+	if root == old.root {
+		return
+	}
+	if root.isRed() {
+		root = root.withColor(BLACK)
+	}
+	l := old.length
+	if added {
+		l++
+	}
+	c.publish(root, l)
+}
+
+// 删除节点，生成并发布新版本
+func (c *COWTree) Del(k int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old := c.version.Load()
+	if _, err := c.Get(k); err != nil {
+		// cowDelete 要求 key 存在
+		return
+	}
+	root := c.delete(old.root, RBTreeKey(k))
+	if root.isRed() {
+		root = root.withColor(BLACK)
+	}
+	c.publish(root, old.length-1)
+}
+
+// 清除所有节点
+func (c *COWTree) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version.Store(&cowVersion{})
+}
+
+func (c *COWTree) publish(root *cowNode, length int) {
+	v := &cowVersion{root: root, length: length}
+	if root != nil {
+		for v.minNode = root; v.minNode.left != nil; v.minNode = v.minNode.left {
+		}
+		for v.maxNode = root; v.maxNode.right != nil; v.maxNode = v.maxNode.right {
+		}
+	}
+	c.version.Store(v)
+}
+
+// 插入时的平衡调整(Okasaki)，把黑色节点下的连续红色节点转换为红色节点下两个黑色子节点
+func cowBalance(a *cowNode, k RBTreeKey, v interface{}, b *cowNode) *cowNode {
+	switch {
+	case a.isRed() && b.isRed():
+		return newCOWNode(RED, a.withColor(BLACK), k, v, b.withColor(BLACK))
+	case a.isRed() && a.left.isRed():
+		return newCOWNode(RED, a.left.withColor(BLACK), a.key, a.value, newCOWNode(BLACK, a.right, k, v, b))
+	case a.isRed() && a.right.isRed():
+		return newCOWNode(RED, newCOWNode(BLACK, a.left, a.key, a.value, a.right.left), a.right.key, a.right.value,
+			newCOWNode(BLACK, a.right.right, k, v, b))
+	case b.isRed() && b.right.isRed():
+		return newCOWNode(RED, newCOWNode(BLACK, a, k, v, b.left), b.key, b.value, b.right.withColor(BLACK))
+	case b.isRed() && b.left.isRed():
+		return newCOWNode(RED, newCOWNode(BLACK, a, k, v, b.left.left), b.left.key, b.left.value,
+			newCOWNode(BLACK, b.left.right, b.key, b.value, b.right))
+	}
+	return newCOWNode(BLACK, a, k, v, b)
+}
+
+// 在 n 中插入节点，返回新的子树和是否新增了节点，没有修改时返回 n
+func (c *COWTree) insert(n *cowNode, k RBTreeKey, v interface{}) (*cowNode, bool) {
+	if n == nil {
+		return newCOWNode(RED, nil, k, v, nil), true
+	}
+	r := c.cmp(k, n.key)
+	switch {
+	case r < 0:
+		l, added := c.insert(n.left, k, v)
+		if l == n.left {
+			return n, false
+		}
+		if n.color == BLACK {
+			return cowBalance(l, n.key, n.value, n.right), added
+		}
+		return newCOWNode(RED, l, n.key, n.value, n.right), added
+	case r > 0:
+		rn, added := c.insert(n.right, k, v)
+		if rn == n.right {
+			return n, false
+		}
+		if n.color == BLACK {
+			return cowBalance(n.left, n.key, n.value, rn), added
+		}
+		return newCOWNode(RED, n.left, n.key, n.value, rn), added
+	}
+	if !c.replace {
+		return n, false
+	}
+	return newCOWNode(n.color, n.left, k, v, n.right), false
+}
+
+// 删除的平衡调整(Kahrs)
+// 黑色节点变为红色，用于降低子树的黑色高度
+func cowSub1(n *cowNode) *cowNode {
+	if n == nil || n.color != BLACK {
+		panic("grbtree: cow tree invariance violation")
+	}
+	return n.withColor(RED)
+}
+
+// 左子树 bl 的黑色高度比右子树少 1 时的调整
+func cowBalLeft(bl *cowNode, k RBTreeKey, v interface{}, r *cowNode) *cowNode {
+	switch {
+	case bl.isRed():
+		return newCOWNode(RED, bl.withColor(BLACK), k, v, r)
+	case r != nil && r.color == BLACK:
+		return cowBalance(bl, k, v, r.withColor(RED))
+	case r.isRed() && r.left != nil && r.left.color == BLACK:
+		return newCOWNode(RED, newCOWNode(BLACK, bl, k, v, r.left.left), r.left.key, r.left.value,
+			cowBalance(r.left.right, r.key, r.value, cowSub1(r.right)))
+	}
+	panic("grbtree: cow tree invariance violation")
+}
+
+// 右子树 br 的黑色高度比左子树少 1 时的调整
+func cowBalRight(l *cowNode, k RBTreeKey, v interface{}, br *cowNode) *cowNode {
+	switch {
+	case br.isRed():
+		return newCOWNode(RED, l, k, v, br.withColor(BLACK))
+	case l != nil && l.color == BLACK:
+		return cowBalance(l.withColor(RED), k, v, br)
+	case l.isRed() && l.right != nil && l.right.color == BLACK:
+		return newCOWNode(RED, cowBalance(cowSub1(l.left), l.key, l.value, l.right.left), l.right.key, l.right.value,
+			newCOWNode(BLACK, l.right.right, k, v, br))
+	}
+	panic("grbtree: cow tree invariance violation")
+}
+
+// 合并删除节点的左右子树
+func cowAppend(a, b *cowNode) *cowNode {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.isRed() && b.isRed():
+		bc := cowAppend(a.right, b.left)
+		if bc.isRed() {
+			return newCOWNode(RED, newCOWNode(RED, a.left, a.key, a.value, bc.left), bc.key, bc.value,
+				newCOWNode(RED, bc.right, b.key, b.value, b.right))
+		}
+		return newCOWNode(RED, a.left, a.key, a.value, newCOWNode(RED, bc, b.key, b.value, b.right))
+	case !a.isRed() && !b.isRed():
+		bc := cowAppend(a.right, b.left)
+		if bc.isRed() {
+			return newCOWNode(RED, newCOWNode(BLACK, a.left, a.key, a.value, bc.left), bc.key, bc.value,
+				newCOWNode(BLACK, bc.right, b.key, b.value, b.right))
+		}
+		return cowBalLeft(a.left, a.key, a.value, newCOWNode(BLACK, bc, b.key, b.value, b.right))
+	case b.isRed():
+		return newCOWNode(RED, cowAppend(a, b.left), b.key, b.value, b.right)
+	}
+	return newCOWNode(RED, a.left, a.key, a.value, cowAppend(a.right, b))
+}
+
+// 删除 n 中的 k，k 必须存在
+func (c *COWTree) delete(n *cowNode, k RBTreeKey) *cowNode {
+	r := c.cmp(k, n.key)
+	switch {
+	case r < 0:
+		if n.left != nil && n.left.color == BLACK {
+			return cowBalLeft(c.delete(n.left, k), n.key, n.value, n.right)
+		}
+		return newCOWNode(RED, c.delete(n.left, k), n.key, n.value, n.right)
+	case r > 0:
+		if n.right != nil && n.right.color == BLACK {
+			return cowBalRight(n.left, n.key, n.value, c.delete(n.right, k))
+		}
+		return newCOWNode(RED, n.left, n.key, n.value, c.delete(n.right, k))
+	}
+	return cowAppend(n.left, n.right)
+}
@@ -0,0 +1,149 @@
+package grbtree
+
+// 按顺序遍历树的游标
+// 游标创建或定位(First/Last/Seek)后，如果树被修改(添加、删除、清除)，
+// 游标的移动将失败，Err 返回 ConcurrentModification 错误；通过游标 Delete 的修改除外
+//
+//	c := tree.Cursor()
+//	for c.Next() {
+//		fmt.Println(c.Key(), c.Value())
+//	}
+//	if err := c.Err(); err != nil {
+//		...
+//	}
+type Cursor struct {
+	t       *RBTree
+	n       *RBTreeNode
+	mod     uint64 // 游标定位时树的修改次数
+	started bool
+	err     error
+
+	// Delete 后游标不指向任何节点，Next/Prev 移动到被删除节点的后继/前驱节点
+	deleted  bool
+	nextNode *RBTreeNode
+	prevNode *RBTreeNode
+}
+
+// 创建游标，游标初始不指向任何节点，第一次 Next 移动到第一个节点，第一次 Prev 移动到最后一个节点
+func (t *RBTree) Cursor() *Cursor {
+	return &Cursor{t: t, mod: t.modCount}
+}
+
+// 检查树是否被修改
+func (c *Cursor) check() bool {
+	if c.err == nil && c.mod != c.t.modCount {
+		c.err = errConcurrentModify
+		c.n = nil
+	}
+	return c.err == nil
+}
+
+// 重新定位到节点 n
+func (c *Cursor) reset(n *RBTreeNode) bool {
+	c.n = n
+	c.mod = c.t.modCount
+	c.started = true
+	c.err = nil
+	c.deleted = false
+	return n != nil
+}
+
+// 移动到第一个节点
+func (c *Cursor) First() bool {
+	return c.reset(c.t.minNode)
+}
+
+// 移动到最后一个节点
+func (c *Cursor) Last() bool {
+	return c.reset(c.t.maxNode)
+}
+
+// 移动到第一个 key >= k 的节点
+func (c *Cursor) Seek(k int) bool {
+	return c.reset(c.t.lowerBound(RBTreeKey(k)))
+}
+
+// 移动到下一个节点，没有下一个节点或树被修改时返回 false
+func (c *Cursor) Next() bool {
+	if !c.check() {
+		return false
+	}
+	if !c.started {
+		return c.First()
+	}
+	if c.deleted {
+		c.n, c.deleted = c.nextNode, false
+	} else if c.n != nil {
+		c.n = c.n.Next()
+	}
+	return c.n != nil
+}
+
+// 移动到上一个节点，没有上一个节点或树被修改时返回 false
+func (c *Cursor) Prev() bool {
+	if !c.check() {
+		return false
+	}
+	if !c.started {
+		return c.Last()
+	}
+	if c.deleted {
+		c.n, c.deleted = c.prevNode, false
+	} else if c.n != nil {
+		c.n = c.n.Prev()
+	}
+	return c.n != nil
+}
+
+// 游标是否指向节点
+func (c *Cursor) Valid() bool {
+	return c.check() && c.n != nil
+}
+
+func (c *Cursor) Key() RBTreeKey {
+	if !c.Valid() {
+		return 0
+	}
+	return c.n.key
+}
+
+func (c *Cursor) Value() interface{} {
+	if !c.Valid() {
+		return nil
+	}
+	return c.n.value
+}
+
+// 修改当前节点的值，不属于结构修改
+func (c *Cursor) SetValue(v interface{}) {
+	if c.Valid() {
+		c.n.value = v
+	}
+}
+
+// 删除当前节点，之后 Next/Prev 移动到被删除节点的后继/前驱节点，不会跳过或重复节点
+//
+//	for c := tree.Cursor(); c.Next(); {
+//		if c.Value() == nil {
+//			c.Delete()
+//		}
+//	}
+func (c *Cursor) Delete() bool {
+	if !c.Valid() {
+		return false
+	}
+	n := c.n
+	// 删除节点不会改变其它节点，前驱、后继节点删除后仍然有效
+	c.prevNode, c.nextNode = n.Prev(), n.Next()
+	c.t.delete(n)
+	c.t.checkValid()
+	c.n = nil
+	c.deleted = true
+	c.mod = c.t.modCount
+	return true
+}
+
+// 遍历过程中树被修改时返回 ConcurrentModification 错误
+func (c *Cursor) Err() error {
+	return c.err
+}
@@ -0,0 +1,77 @@
+package grbtree
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 延迟队列，元素按到期时间排序，到期后才能取出，可以被多个 goroutine 同时使用
+// 基于多值模式的树，key 为到期时间的 UnixNano，到期时间相同时按插入顺序取出
+//
+//	q := grbtree.NewDelayQueue()
+//	q.Put(time.Now().Add(time.Second), "retry")
+//	item, err := q.Take(ctx)
+type DelayQueue struct {
+	mu   sync.Mutex
+	t    *RBTree
+	wake chan struct{} // 有更早到期的元素加入时关闭，唤醒等待中的 Take
+}
+
+func NewDelayQueue() *DelayQueue {
+	return &DelayQueue{t: NewMultiRBTree(), wake: make(chan struct{})}
+}
+
+// 添加在 deadline 到期的元素
+func (q *DelayQueue) Put(deadline time.Time, item interface{}) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	k := RBTreeKey(deadline.UnixNano())
+	min, _, err := q.t.GetMin()
+	q.t.add(k, item)
+	if err != nil || k < min {
+		close(q.wake)
+		q.wake = make(chan struct{})
+	}
+}
+
+// 取出最早到期的元素，没有到期的元素时阻塞，直到有元素到期或 ctx 结束
+func (q *DelayQueue) Take(ctx context.Context) (interface{}, error) {
+	for {
+		var timer *time.Timer
+		var expired <-chan time.Time
+		q.mu.Lock()
+		k, v, err := q.t.GetMin()
+		if err == nil {
+			d := time.Until(time.Unix(0, int64(k)))
+			if d <= 0 {
+				q.t.PopMin()
+				q.mu.Unlock()
+				return v, nil
+			}
+			timer = time.NewTimer(d)
+			expired = timer.C
+		}
+		wake := q.wake
+		q.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+		case <-wake:
+		case <-expired:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// 元素数量，包括未到期的元素
+func (q *DelayQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.t.Len()
+}
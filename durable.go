@@ -0,0 +1,392 @@
+package grbtree
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// 预写日志中的操作
+const (
+	walAdd byte = 1
+	walDel byte = 2
+)
+
+const (
+	snapshotFile = "snapshot"
+	walFile      = "wal"
+)
+
+// 持久化的树，每次 Add/Del 先追加到目录中的预写日志(wal)再修改树，
+// 日志记录数量达到阈值时写入快照(snapshot)并清空日志
+// 打开时加载快照并重放日志，只有日志末尾写入不完整的记录会被丢弃，
+// 日志中间的记录损坏或值无法解码时返回错误，不会修改日志
+//
+//	dt, err := grbtree.OpenDurableTree("./data")
+//	dt.Add(1, "a")
+//	dt.Close()
+//
+// 每条日志记录有递增的序号(lsn)，快照中保存写入快照时最后一条记录的序号，
+// 重放时跳过已包含在快照中的记录，避免写入快照后、清空日志前崩溃时重复执行
+//
+// wal 记录格式: 长度(4 bytes 大端) | crc32(4 bytes 大端) | 操作(1 byte) | lsn(uvarint) | key(varint) | 值
+// snapshot 格式: lsn(8 bytes 大端) | Encode 写入的树
+type DurableTree struct {
+	mu   sync.Mutex
+	t    *RBTree
+	dir  string
+	wal  *os.File
+	conf durableConfig
+	ops  int    // 日志中的记录数
+	lsn  uint64 // 最后一条日志记录的序号
+	err  error  // 写入失败后无法恢复日志时的错误，之后拒绝所有写入
+}
+
+// 一条日志记录
+type walRecord struct {
+	op  byte
+	lsn uint64
+	k   RBTreeKey
+	v   interface{}
+}
+
+// OpenDurableTree 的配置项
+type DurableOption func(c *durableConfig)
+
+type durableConfig struct {
+	snapshotEvery int
+	sync          bool
+	treeOpts      []Option
+}
+
+// 日志记录数达到 n 时自动写入快照，n <= 0 时不自动写入，默认 10000
+func WithSnapshotEvery(n int) DurableOption {
+	return func(c *durableConfig) {
+		c.snapshotEvery = n
+	}
+}
+
+// 每次写入日志后调用 fsync，默认不调用
+func WithSyncWrites(on bool) DurableOption {
+	return func(c *durableConfig) {
+		c.sync = on
+	}
+}
+
+// 创建树使用的配置项，值的编解码由 WithValueCodec 设置
+func WithTreeOptions(opts ...Option) DurableOption {
+	return func(c *durableConfig) {
+		c.treeOpts = append(c.treeOpts, opts...)
+	}
+}
+
+// 打开 dir 目录中的持久化树，目录不存在时创建
+func OpenDurableTree(dir string, opts ...DurableOption) (*DurableTree, error) {
+	conf := durableConfig{snapshotEvery: 10000}
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	d := &DurableTree{t: NewRBTree(conf.treeOpts...), dir: dir, conf: conf}
+	if err := d.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	wal, err := os.OpenFile(filepath.Join(dir, walFile), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	d.wal = wal
+	if err := d.replay(); err != nil {
+		wal.Close()
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *DurableTree) loadSnapshot() error {
+	f, err := os.Open(filepath.Join(d.dir, snapshotFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	r := bufio.NewReader(f)
+	var lsn [8]byte
+	if _, err := io.ReadFull(r, lsn[:]); err != nil {
+		return errBadFormat
+	}
+	d.lsn = binary.BigEndian.Uint64(lsn[:])
+	return d.t.Decode(r)
+}
+
+// 重放日志，并截掉最后不完整的记录
+func (d *DurableTree) replay() error {
+	r := bufio.NewReader(d.wal)
+	var offset int64
+	for {
+		rec, n, err := d.readRecord(r)
+		if err == io.EOF {
+			break
+		}
+		if err == io.ErrUnexpectedEOF || isTornTail(r, err) {
+			// 写入最后一条记录时崩溃，截掉不完整的记录
+			if err := d.wal.Truncate(offset); err != nil {
+				return err
+			}
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("grbtree: wal record at offset %d: %w", offset, err)
+		}
+		offset += n
+		d.ops++
+		if rec.lsn <= d.lsn {
+			// 已包含在快照中
+			continue
+		}
+		if rec.op == walAdd {
+			d.t.add(rec.k, rec.v)
+		} else {
+			d.t.del(rec.k)
+		}
+		d.lsn = rec.lsn
+	}
+	_, err := d.wal.Seek(offset, io.SeekStart)
+	return err
+}
+
+// 校验失败的记录是否是日志的最后一条
+// 崩溃时最后一条记录的空间可能已分配但数据没有全部写入(全为 0 或只写入了部分扇区)，
+// 长度完整但校验失败，与读取不完整一样视为未写完的记录；后面还有数据时是日志损坏
+func isTornTail(r *bufio.Reader, err error) bool {
+	if err != errBadChecksum && err != errBadFormat {
+		return false
+	}
+	_, perr := r.Peek(1)
+	return perr == io.EOF
+}
+
+// 读取一条日志记录，返回记录的长度
+// 没有更多记录时返回 io.EOF，最后一条记录不完整时返回 io.ErrUnexpectedEOF
+func (d *DurableTree) readRecord(r io.Reader) (rec walRecord, n int64, err error) {
+	var head [8]byte
+	if _, err = io.ReadFull(r, head[:]); err != nil {
+		return
+	}
+	l := binary.BigEndian.Uint32(head[:4])
+	// 按实际读取的数据分配内存，避免错误的长度导致分配过大的内存
+	payload, err := io.ReadAll(io.LimitReader(r, int64(l)))
+	if err != nil {
+		return
+	}
+	if uint32(len(payload)) != l {
+		err = io.ErrUnexpectedEOF
+		return
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(head[4:]) {
+		err = errBadChecksum
+		return
+	}
+	if len(payload) == 0 || payload[0] != walAdd && payload[0] != walDel {
+		err = errBadFormat
+		return
+	}
+	rec.op = payload[0]
+	b := payload[1:]
+	lsn, ll := binary.Uvarint(b)
+	if ll <= 0 {
+		err = errBadFormat
+		return
+	}
+	b = b[ll:]
+	key, kl := binary.Varint(b)
+	if kl <= 0 {
+		err = errBadFormat
+		return
+	}
+	rec.lsn, rec.k = lsn, RBTreeKey(key)
+	if rec.op == walAdd {
+		if rec.v, err = d.t.valueCodec().DecodeValue(b[kl:]); err != nil {
+			// 包装后不会与表示记录不完整的 io.ErrUnexpectedEOF 混淆
+			err = fmt.Errorf("decode value: %w", err)
+			return
+		}
+	}
+	return rec, int64(len(head) + len(payload)), nil
+}
+
+// 追加一条日志记录
+func (d *DurableTree) appendRecord(op byte, k RBTreeKey, v interface{}) error {
+	payload := make([]byte, 1+2*binary.MaxVarintLen64)
+	payload[0] = op
+	l := 1 + binary.PutUvarint(payload[1:], d.lsn+1)
+	payload = payload[:l+binary.PutVarint(payload[l:], int64(k))]
+	if op == walAdd {
+		b, err := d.t.valueCodec().EncodeValue(v)
+		if err != nil {
+			return err
+		}
+		payload = append(payload, b...)
+	}
+	rec := make([]byte, 8, 8+len(payload))
+	binary.BigEndian.PutUint32(rec[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(rec[4:], crc32.ChecksumIEEE(payload))
+	if d.err != nil {
+		return d.err
+	}
+	off, err := d.wal.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err = d.wal.Write(append(rec, payload...)); err == nil && d.conf.sync {
+		err = d.wal.Sync()
+	}
+	if err != nil {
+		// 写入部分记录后失败时截掉这部分数据，否则之后的记录会被当作它的内容，日志无法再打开
+		if terr := d.rollback(off); terr != nil {
+			d.err = fmt.Errorf("grbtree: wal is unusable after write error %v: %w", err, terr)
+		}
+		return err
+	}
+	d.ops++
+	d.lsn++
+	return nil
+}
+
+// 把日志截断到 off 并从 off 继续写入
+func (d *DurableTree) rollback(off int64) error {
+	if err := d.wal.Truncate(off); err != nil {
+		return err
+	}
+	_, err := d.wal.Seek(off, io.SeekStart)
+	return err
+}
+
+// 日志记录数达到阈值时写入快照，需要在修改树之后调用
+func (d *DurableTree) maybeSnapshot() error {
+	if d.conf.snapshotEvery > 0 && d.ops >= d.conf.snapshotEvery {
+		return d.snapshot()
+	}
+	return nil
+}
+
+// 写入快照，先写临时文件再重命名，保证快照文件完整，然后清空日志
+func (d *DurableTree) snapshot() error {
+	tmp := filepath.Join(d.dir, snapshotFile+".tmp")
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	var lsn [8]byte
+	binary.BigEndian.PutUint64(lsn[:], d.lsn)
+	if _, err = w.Write(lsn[:]); err == nil {
+		err = d.t.Encode(w)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(d.dir, snapshotFile)); err != nil {
+		return err
+	}
+	// 重命名在目录同步到磁盘后才持久，否则崩溃后可能仍是旧快照而日志已被清空
+	if err := syncDir(d.dir); err != nil {
+		return err
+	}
+	if err := d.wal.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := d.wal.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	d.ops = 0
+	return nil
+}
+
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
+
+// 添加节点，写入日志成功后才修改树
+func (d *DurableTree) Add(k int, v interface{}) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.appendRecord(walAdd, RBTreeKey(k), v); err != nil {
+		return err
+	}
+	d.t.Add(k, v)
+	return d.maybeSnapshot()
+}
+
+// 删除节点，写入日志成功后才修改树
+func (d *DurableTree) Del(k int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.appendRecord(walDel, RBTreeKey(k), nil); err != nil {
+		return err
+	}
+	d.t.Del(k)
+	return d.maybeSnapshot()
+}
+
+func (d *DurableTree) Get(k int) (interface{}, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.t.Get(k)
+}
+
+func (d *DurableTree) GetMin() (RBTreeKey, interface{}, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.t.GetMin()
+}
+
+func (d *DurableTree) GetMax() (RBTreeKey, interface{}, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.t.GetMax()
+}
+
+func (d *DurableTree) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.t.Len()
+}
+
+// 立即写入快照并清空日志
+func (d *DurableTree) Snapshot() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.snapshot()
+}
+
+// 关闭日志文件，不会写入快照
+func (d *DurableTree) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.wal.Close()
+}
@@ -6,4 +6,14 @@ var (
 	errKeyAlreadyExists = errors.New("KeyAlreadyExists")
 	errKeyNotExists     = errors.New("KeyNotExists")
 	errNotNode          = errors.New("NotNode")
+	errIndexOutOfRange  = errors.New("IndexOutOfRange")
+	errBadFormat        = errors.New("BadFormat")
+	errBadVersion       = errors.New("UnsupportedVersion")
+	errBadChecksum      = errors.New("ChecksumMismatch")
+	errUnsorted         = errors.New("KeysNotSorted")
+	errConcurrentModify = errors.New("ConcurrentModification")
+	errNoFreeID         = errors.New("NoFreeID")
+	errIDNotAllocated   = errors.New("IDNotAllocated")
+	errNoFreeBlock      = errors.New("NoFreeBlock")
+	errNotAllocated     = errors.New("NotAllocated")
 )
\ No newline at end of file
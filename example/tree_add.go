@@ -3,7 +3,7 @@ package example
 import (
 	"fmt"
 
-	"github.com/chr193997060/grbtree"
+	"github.com/chr193997060/grbtree/v2"
 )
 
 func main() {
@@ -1,3 +1,3 @@
-module github.com/chr193997060/grbtree
+module github.com/chr193997060/grbtree/v2
 
-go 1.18
+go 1.19
@@ -15,20 +15,29 @@ const (
 
 type RBTreeKey int64
 
+// 树的节点，key 和颜色只能由树修改，保证树的有序和平衡
 type RBTreeNode struct {
-	Key    RBTreeKey
-	Value  interface{}
-	Color  bool
+	key    RBTreeKey
+	value  interface{}
+	color  bool
+	size   uint64 // 子树的权重之和，普通节点权重为 1
 	parent *RBTreeNode
 	left   *RBTreeNode
 	right  *RBTreeNode
 }
 
 type RBTree struct {
-	Root    *RBTreeNode
-	Len     uint32
+	root    *RBTreeNode
+	length  uint32
 	minNode *RBTreeNode
 	maxNode *RBTreeNode
+
+	cmp        func(a, b RBTreeKey) int // key 比较函数，nil 时按数值从小到大排序
+	dup        DuplicatePolicy          // 添加重复 key 时的处理方式
+	validation bool                     // 每次修改后检查红黑树的性质
+	pool       []RBTreeNode             // 预分配的节点
+	codec      ValueCodec               // 序列化时值的编解码，nil 时使用 GobCodec
+	modCount   uint64                   // 结构修改的次数，用于检测遍历时树被修改
 }
 
 
@@ -41,15 +50,38 @@ func (k *RBTreeKey)ToStr() string {
 }
 
 
+// Deprecated: 节点由树在 Add 时创建，单独创建的节点无法加入树中
 func NewRBTreeNode(key int, val interface{}) *RBTreeNode {
 	return &RBTreeNode{
-		Key:   RBTreeKey(key),
-		Value: val,
-		Color: RED,
+		key:   RBTreeKey(key),
+		value: val,
+		color: RED,
 	}
 }
 
 
+func (n *RBTreeNode) Key() RBTreeKey {
+	return n.key
+}
+
+
+func (n *RBTreeNode) Value() interface{} {
+	return n.value
+}
+
+
+// 修改节点的值，不影响树的结构
+func (n *RBTreeNode) SetValue(v interface{}) {
+	n.value = v
+}
+
+
+// 节点是否是红色
+func (n *RBTreeNode) IsRed() bool {
+	return !n.isBlack()
+}
+
+
 func (n *RBTreeNode) GetParent() *RBTreeNode {
 	return n.parent
 }
@@ -70,7 +102,30 @@ func (n *RBTreeNode) isBlack() bool {
 	if n == nil {
 		return true
 	} else {
-		return !n.Color
+		return !n.color
+	}
+}
+
+
+// 子树权重之和，nil 节点为 0
+func (n *RBTreeNode) subSize() uint64 {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+
+// 节点自身的权重
+func (n *RBTreeNode) weight() uint64 {
+	return n.size - n.left.subSize() - n.right.subSize()
+}
+
+
+// 节点及其所有祖先节点的子树权重加上 d，d 可以是负数的补码
+func (n *RBTreeNode) addSize(d uint64) {
+	for ; n != nil; n = n.parent {
+		n.size += d
 	}
 }
 
@@ -110,14 +165,19 @@ func (t *RBTree) leftRotate(n *RBTreeNode) {
 	//      / \              /  \
 	//     7  11            3    7
 	retNode := n.right
+	// 旋转后 retNode 的子树即原先 n 的子树
+	retNode.size, n.size = n.size, n.size-retNode.size+retNode.left.subSize()
 	n.right = retNode.left
+	if n.right != nil {
+		n.right.parent = n
+	}
 
 	retNode.left = n
 	retNode.parent = n.parent
 	n.parent = retNode
 	
 	if retNode.parent == nil {
-		t.Root = retNode
+		t.root = retNode
 	}else if retNode.parent.left == n {
 		retNode.parent.left = retNode
 	}else{
@@ -134,14 +194,18 @@ func (t *RBTree) rightRotate(n *RBTreeNode) {
 	//  / \                        /  \
 	// 3   7                      7    11
 	retNode := n.left
+	retNode.size, n.size = n.size, n.size-retNode.size+retNode.right.subSize()
 	n.left = retNode.right
+	if n.left != nil {
+		n.left.parent = n
+	}
 
 	retNode.right = n
 	retNode.parent = n.parent
 	n.parent = retNode
 
 	if retNode.parent == nil {
-		t.Root = retNode
+		t.root = retNode
 	}else if retNode.parent.left == n {
 		retNode.parent.left = retNode
 	}else{
@@ -150,16 +214,49 @@ func (t *RBTree) rightRotate(n *RBTreeNode) {
 }
 
 
+// 后继节点，不存在或节点已从树中删除则返回 nil
+func (n *RBTreeNode) Next() *RBTreeNode {
+	if n.right != nil {
+		n = n.right
+		for n.left != nil {
+			n = n.left
+		}
+		return n
+	}
+	for n.parent != nil && n.parent.right == n {
+		n = n.parent
+	}
+	return n.parent
+}
+
+
+// 前驱节点，不存在或节点已从树中删除则返回 nil
+func (n *RBTreeNode) Prev() *RBTreeNode {
+	if n.left != nil {
+		n = n.left
+		for n.right != nil {
+			n = n.right
+		}
+		return n
+	}
+	for n.parent != nil && n.parent.left == n {
+		n = n.parent
+	}
+	return n.parent
+}
+
+
 // 查找k对应节点和k最接近节点，k 不存在则返回第一个返回值为 nil
 func (t *RBTree) findNodeAndRecentNode(k RBTreeKey) (*RBTreeNode, *RBTreeNode) {
 	var recent *RBTreeNode
 	var fnode *RBTreeNode
-	fnode = t.Root
+	fnode = t.root
 	for fnode != nil {
 		recent = fnode
-		if k < fnode.Key {
+		c := t.compare(k, fnode.key)
+		if c < 0 {
 			fnode = fnode.left
-		} else if k > fnode.Key {
+		} else if c > 0 {
 			fnode = fnode.right
 		} else {
 			recent = fnode.parent
@@ -170,6 +267,70 @@ func (t *RBTree) findNodeAndRecentNode(k RBTreeKey) (*RBTreeNode, *RBTreeNode) {
 }
 
 
+// 查找第一个 key >= k 的节点，多值模式下即为 k 最早插入的节点
+func (t *RBTree) lowerBound(k RBTreeKey) *RBTreeNode {
+	var ret *RBTreeNode
+	n := t.root
+	for n != nil {
+		if t.compare(n.key, k) < 0 {
+			n = n.right
+		} else {
+			ret = n
+			n = n.left
+		}
+	}
+	return ret
+}
+
+
+// 按权重查找第 i 个(从 0 开始)元素所在的节点
+func (t *RBTree) selectNode(i uint64) *RBTreeNode {
+	n := t.root
+	for n != nil {
+		ls := n.left.subSize()
+		if i < ls {
+			n = n.left
+		} else if i -= ls; i < n.weight() {
+			return n
+		} else {
+			i -= n.weight()
+			n = n.right
+		}
+	}
+	return nil
+}
+
+
+// key 小于 k 的元素的权重之和
+func (t *RBTree) rank(k RBTreeKey) uint64 {
+	var r uint64
+	n := t.root
+	for n != nil {
+		if t.compare(n.key, k) < 0 {
+			r += n.size - n.right.subSize()
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return r
+}
+
+
+// 查找 k 对应的节点，多值模式下返回最早插入的节点
+func (t *RBTree) findNode(k RBTreeKey) *RBTreeNode {
+	if t.dup == DuplicateAllow {
+		n := t.lowerBound(k)
+		if n != nil && t.compare(n.key, k) == 0 {
+			return n
+		}
+		return nil
+	}
+	n, _ := t.findNodeAndRecentNode(k)
+	return n
+}
+
+
 // 添加节点后的调整
 func (t *RBTree) insertFixUp(n *RBTreeNode) {
 	for !n.parent.isBlack() {
@@ -178,9 +339,9 @@ func (t *RBTree) insertFixUp(n *RBTreeNode) {
 			// 插入的节点的父节点和叔叔节点为红色，则：
 			// 1）把父节点和叔叔节点设为黑色；2）把爷爷节点设为红色；
 			// 3）把指针定位到爷爷节点作为当前需要操作的节点，再根据变换规则来进行判断操作
-			n.parent.Color = BLACK
-			uncleanNode.Color = BLACK
-			n.parent.parent.Color = RED
+			n.parent.color = BLACK
+			uncleanNode.color = BLACK
+			n.parent.parent.color = RED
 			// 对爷爷节点进行调整操作
 			n = n.parent.parent
 		} else if n == n.parent.left {
@@ -188,15 +349,15 @@ func (t *RBTree) insertFixUp(n *RBTreeNode) {
 				// LL 插入情况
 				// LL情况：父节点为爷爷节点的左节点，插入节点为父节点的左节点
 				// 1.把父节点变为黑色. 2.把爷爷节点变为红色. 3.以爷爷节点右旋转.
-				n.parent.Color = BLACK
-				n.parent.parent.Color = RED
+				n.parent.color = BLACK
+				n.parent.parent.color = RED
 				n = n.parent.parent
 				t.rightRotate(n)
 			}else{
 				// RL 情况: 父节点为爷爷节点的右节点，插入节点为父节点的左节点
 				// 1.插入节点变为黑色. 2.把爷爷节点变为红色. 3.以父节点右旋转. 4.以爷爷节点左旋转.
-				n.Color = BLACK
-				n.parent.parent.Color = RED
+				n.color = BLACK
+				n.parent.parent.color = RED
 				t.rightRotate(n.parent)
 				n = n.parent
 				t.leftRotate(n)
@@ -205,44 +366,86 @@ func (t *RBTree) insertFixUp(n *RBTreeNode) {
 			if n.parent == n.parent.parent.right {
 				// RR情况：父节点为爷爷节点的右节点，插入节点为父节点的右节点
 				// 1.把父节点变为黑色. 2.把爷爷节点变为红色. 3.以爷爷节点左旋转.
-				n.parent.Color = BLACK
-				n.parent.parent.Color = RED
+				n.parent.color = BLACK
+				n.parent.parent.color = RED
 				n = n.parent.parent
 				t.leftRotate(n)
 			}else{
 				// LR情况：父节点为爷爷节点的左节点，插入节点为父节点的右节点
 				// 1.插入节点变为黑色. 2.把爷爷节点变为红色. 3.以父节点左旋转. 4.以爷爷节点右旋转.
-				n.Color = BLACK
-				n.parent.parent.Color = RED
+				n.color = BLACK
+				n.parent.parent.color = RED
 				t.leftRotate(n.parent)
 				n = n.parent
 				t.rightRotate(n)
 			}
 		}
 	}
-	t.Root.Color = BLACK
+	t.root.color = BLACK
 }
 
 
 func (t *RBTree) insert(i_node *RBTreeNode) error {
-	kn, nf := t.findNodeAndRecentNode(i_node.Key)
-	if kn != nil {
-		return errKeyAlreadyExists
+	var nf *RBTreeNode
+	if t.dup == DuplicateAllow {
+		// 多值模式下相同的 key 放到右边，保证相同 key 按插入顺序排列
+		for n := t.root; n != nil; {
+			nf = n
+			if t.compare(i_node.key, n.key) < 0 {
+				n = n.left
+			} else {
+				n = n.right
+			}
+		}
+	} else {
+		var kn *RBTreeNode
+		kn, nf = t.findNodeAndRecentNode(i_node.key)
+		if kn != nil {
+			if t.dup == DuplicateReplace {
+				kn.value = i_node.value
+				return nil
+			}
+			return errKeyAlreadyExists
+		}
 	}
-	if i_node.Key < nf.Key {
-		nf.left = i_node
+	t.link(nf, i_node, t.compare(i_node.key, nf.key) < 0)
+	return nil
+}
+
+
+// 把节点 n 添加为 p 的左(left 为 true)或右子节点，然后调整，不比较 key
+func (t *RBTree) link(p *RBTreeNode, n *RBTreeNode, left bool) {
+	n.color = RED
+	n.parent = p
+	if left {
+		p.left = n
+		if t.minNode == p {
+			t.minNode = n
+		}
 	} else {
-		nf.right = i_node
+		p.right = n
+		if t.maxNode == p {
+			t.maxNode = n
+		}
 	}
-	i_node.parent = nf
-	t.Len++
-	if i_node.Key < t.minNode.Key {
-		t.minNode = i_node
+	p.addSize(n.size)
+	t.length++
+	t.modCount++
+	t.insertFixUp(n)
+}
+
+
+// 把节点添加为最后一个节点，节点的 key 需要不小于当前最大节点的 key
+// 用于按顺序添加节点，均摊 O(1) 次旋转
+func (t *RBTree) pushBack(n *RBTreeNode) error {
+	if t.root == nil {
+		return t.addNode(n)
 	}
-	if i_node.Key > t.maxNode.Key {
-		t.maxNode = i_node
+	c := t.compare(t.maxNode.key, n.key)
+	if c > 0 || c == 0 && t.dup != DuplicateAllow {
+		return errUnsorted
 	}
-	t.insertFixUp(i_node)
+	t.link(t.maxNode, n, false)
 	return nil
 }
 
@@ -256,7 +459,7 @@ func (t *RBTree) parentReplaceChild(n *RBTreeNode, new *RBTreeNode){
 			n.parent.right = new
 		}
 	}else{
-		t.Root = new
+		t.root = new
 	}
 	if new != nil {
 		new.parent = n.parent
@@ -265,9 +468,13 @@ func (t *RBTree) parentReplaceChild(n *RBTreeNode, new *RBTreeNode){
 
 
 func (t *RBTree) delete(n *RBTreeNode) {
+	if n.left == nil || n.right == nil {
+		// 节点将被移除，先从祖先节点的权重中减去
+		n.parent.addSize(-n.weight())
+	}
 	if n.left == nil && n.right == nil {
 		// 删除节点没有子节点，即为叶节点，
-		if n.Color {
+		if n.color {
 			// 叶子节点为红色直接将该节点删除（替换父节点指向nil）
 			n.parent.replaceChild(n, nil)
 		}else{
@@ -281,7 +488,7 @@ func (t *RBTree) delete(n *RBTreeNode) {
 				}
 			}else{
 				// 根节点直接删除
-				t.Root = nil
+				t.root = nil
 			}
 		}
 		if t.minNode == n {
@@ -295,40 +502,75 @@ func (t *RBTree) delete(n *RBTreeNode) {
 		// 1. 删除节点(父节点指向删除节点的子节点)，删除节点只能是黑色，子节点也只能是红色（删除节点只一个子节点，若删除节点子节点不是红色则到叶子节点的黑色节点数将不一样）
 		// 2. 修改子节点为黑色
 		t.parentReplaceChild(n, n.right)
-		n.right.Color = BLACK
-		if t.minNode.Key == n.Key {
+		n.right.color = BLACK
+		if t.minNode == n {
 			t.minNode = n.right
 		}
 	}else if n.right == nil {
 		t.parentReplaceChild(n, n.left)
-		n.left.Color = BLACK
-		if t.maxNode.Key == n.Key {
+		n.left.color = BLACK
+		if t.maxNode == n {
 			t.maxNode = n.left
 		}
 	}else {
-		// 找到n的后继节点，把n替换成后继节点的k,值，转换为删除n的后继节点
-		// n 有两个子节点，所以不可能是最大\最小节点
-		var nextNode *RBTreeNode
-		if n.right.left == nil {
-			nextNode = n.right
-		}else {
-			nextNode = n.right.left
+		// 找到n的后继节点，交换n和后继节点在树中的位置，转换为删除没有左子节点的n
+		// 不交换节点的k,值，其它地方持有的后继节点指针仍然有效
+		// 后继节点为右子树的最左节点
+		nextNode := n.right
+		for nextNode.left != nil {
+			nextNode = nextNode.left
 		}
-		n.Key = nextNode.Key
-		n.Value = nextNode.Value
-		t.delete(nextNode)
+		t.swapWithSuccessor(n, nextNode)
+		t.delete(n)
 		return
 	}
-	t.Len -= 1
+	t.length -= 1
+	t.modCount++
+	// 已移除的节点 size 为 0
+	n.parent, n.left, n.right = nil, nil, nil
+	n.size = 0
+}
+
+
+// 交换 n 和其后继节点 s 在树中的位置，包括颜色和子树权重，n 必须有两个子节点
+func (t *RBTree) swapWithSuccessor(n, s *RBTreeNode) {
+	nw, sw := n.weight(), s.weight()
+	nl, nr, sp, sr := n.left, n.right, s.parent, s.right
+	n.color, s.color = s.color, n.color
+	n.size, s.size = s.size, n.size
+
+	// s 放到 n 的位置
+	t.parentReplaceChild(n, s)
+	s.left = nl
+	nl.parent = s
+	if sp == n {
+		s.right = n
+		n.parent = s
+	} else {
+		s.right = nr
+		nr.parent = s
+		sp.left = n
+		n.parent = sp
+	}
+	// n 放到 s 的位置
+	n.left = nil
+	n.right = sr
+	if sr != nil {
+		sr.parent = n
+	}
+	// n 原先的位置到 s 原先的位置之间的子树，包含的节点由 s 变为 n
+	for p := n; p != s; p = p.parent {
+		p.size += nw - sw
+	}
 }
 
 
 // 删除节点的兄弟节点右红色子节点的情况下的颜色操作
 func (t *RBTree) deleteNodeRedBroChildColorRevise(n *RBTreeNode) {
 	c := n.parent.parent
-	c.Color = n.parent.Color
-	c.left.Color = BLACK
-	c.right.Color = BLACK
+	c.color = n.parent.color
+	c.left.color = BLACK
+	c.right.color = BLACK
 }
 
 
@@ -365,10 +607,10 @@ func (t *RBTree) deleteFixUp(n *RBTreeNode) {
 				t.deleteNodeRedBroChildColorRevise(n)
 			}else{
 				// 兄弟节点没有红色子节点
-				broNode.Color = RED
+				broNode.color = RED
 				if !n.parent.isBlack(){
 					// 父节点是红色的，把父节点颜色替换成黑色
-					n.parent.Color = BLACK
+					n.parent.color = BLACK
 				}else{
 					// 父节点不是红色，则需要以父节点为删除节点进行调整
 					t.deleteFixUp(n.parent)
@@ -379,8 +621,8 @@ func (t *RBTree) deleteFixUp(n *RBTreeNode) {
 			// 以父节点旋转，父节点和兄弟节点替换颜色，这样父节点就变成和黑色，变成了上面的情况
 			// RR
 			t.leftRotate(n.parent)
-			n.parent.Color = RED
-			broNode.Color = BLACK
+			n.parent.color = RED
+			broNode.color = BLACK
 			t.deleteFixUp(n)
 		}
 	}else {
@@ -402,9 +644,9 @@ func (t *RBTree) deleteFixUp(n *RBTreeNode) {
 				t.rightRotate(n.parent)
 				t.deleteNodeRedBroChildColorRevise(n)
 			}else{
-				broNode.Color = RED
+				broNode.color = RED
 				if !n.parent.isBlack() {
-					n.parent.Color = BLACK
+					n.parent.color = BLACK
 				}else{
 					t.deleteFixUp(n.parent)
 				}
@@ -412,83 +654,220 @@ func (t *RBTree) deleteFixUp(n *RBTreeNode) {
 		}else{
 			// LL
 			t.rightRotate(n.parent)
-			n.parent.Color = RED
-			broNode.Color = BLACK
+			n.parent.color = RED
+			broNode.color = BLACK
 			t.deleteFixUp(n)
 		}
 	}
 }
 
+// 根节点，树为空时返回 nil
+func (t *RBTree) Root() *RBTreeNode {
+	return t.root
+}
+
+// 节点数量
+func (t *RBTree) Len() int {
+	return int(t.length)
+}
+
 // tree := grbtree.NewRBTree()
-func NewRBTree() *RBTree {
-	return &RBTree{}
+// tree := grbtree.NewRBTree(grbtree.WithDuplicatePolicy(grbtree.DuplicateReplace))
+func NewRBTree(opts ...Option) *RBTree {
+	t := &RBTree{}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// 多值模式的树，相同的 key 可以添加多次，按插入顺序保存
+// 等同于 NewRBTree(WithDuplicatePolicy(DuplicateAllow))
+// tree := grbtree.NewMultiRBTree()
+func NewMultiRBTree() *RBTree {
+	return NewRBTree(WithDuplicatePolicy(DuplicateAllow))
 }
 
+// 多值模式下返回 k 最早插入的值
 func (t *RBTree) Get(k int) (interface{}, error) {
-	n, _ := t.findNodeAndRecentNode(RBTreeKey(k))
+	n := t.findNode(RBTreeKey(k))
 	if n == nil {
 		return nil, errKeyNotExists
 	}
-	return n.Value, nil
+	return n.value, nil
 }
 
 func (t *RBTree) GetMax() (k RBTreeKey, v interface {}, err error){
-	if t.Root == nil {
+	if t.root == nil {
 		return k, nil, errNotNode
 	}
-	k = t.maxNode.Key
-	v = t.maxNode.Value
+	k = t.maxNode.key
+	v = t.maxNode.value
 	return k, v, err
 }
 
 func (t *RBTree) GetMin() (k RBTreeKey, v interface {}, err error){
-	if t.Root == nil {
+	if t.root == nil {
 		return k, nil, errNotNode
 	}
-	k = t.minNode.Key
-	v = t.minNode.Value
+	k = t.minNode.key
+	v = t.minNode.value
 	return k, v, err
 }
 
+// 删除并返回最小节点的 key 和值
+func (t *RBTree) PopMin() (k RBTreeKey, v interface {}, err error){
+	if t.root == nil {
+		return k, nil, errNotNode
+	}
+	n := t.minNode
+	t.delete(n)
+	t.checkValid()
+	return n.key, n.value, nil
+}
+
+// 删除并返回最大节点的 key 和值
+func (t *RBTree) PopMax() (k RBTreeKey, v interface {}, err error){
+	if t.root == nil {
+		return k, nil, errNotNode
+	}
+	n := t.maxNode
+	t.delete(n)
+	t.checkValid()
+	return n.key, n.value, nil
+}
+
 // 添加节点到树中
 func (t *RBTree) Add(k int, v interface{}) {
-	if t.Root == nil {
-		t.Root = &RBTreeNode{
-			Key:   RBTreeKey(k),
-			Value: v,
-			Color: BLACK,
-		}
-		t.minNode = t.Root
-		t.maxNode = t.Root
-		t.Len = 1
-		return
+	t.add(RBTreeKey(k), v)
+	t.checkValid()
+}
+
+// 添加节点，key 已存在时返回 errKeyAlreadyExists
+func (t *RBTree) add(k RBTreeKey, v interface{}) error {
+	return t.addNode(t.newNode(k, v))
+}
+
+// 添加节点，传入节点的 size 即为节点的权重
+func (t *RBTree) addNode(n *RBTreeNode) error {
+	if t.root == nil {
+		n.color = BLACK
+		t.root = n
+		t.minNode = n
+		t.maxNode = n
+		t.length = 1
+		t.modCount++
+		return nil
 	}
-	node := NewRBTreeNode(k, v)
-	t.insert(node)
+	n.color = RED
+	return t.insert(n)
 }
 
-// 删除树中的节点
+// 删除树中的节点，多值模式下删除 k 的所有值
 func (t *RBTree) Del(k int) {
-	if t.Root == nil {
-		return
+	t.del(RBTreeKey(k))
+	t.checkValid()
+}
+
+func (t *RBTree) del(k RBTreeKey) {
+	for t.root != nil {
+		n := t.findNode(k)
+		if n == nil {
+			return
+		}
+		t.delete(n)
 	}
-	n, _ := t.findNodeAndRecentNode(RBTreeKey(k))
-	if n == nil {
-		return
+}
+
+
+// 获取 k 对应的节点，多值模式下返回最早插入的节点
+// 节点在被删除前一直有效，可以用于修改值或通过 Next/Prev 遍历
+func (t *RBTree) GetNode(k int) *RBTreeNode {
+	return t.findNode(RBTreeKey(k))
+}
+
+
+// 最小节点，树为空时返回 nil
+func (t *RBTree) MinNode() *RBTreeNode {
+	return t.minNode
+}
+
+
+// 最大节点，树为空时返回 nil
+func (t *RBTree) MaxNode() *RBTreeNode {
+	return t.maxNode
+}
+
+
+// 删除树中的节点 n，n 已被删除或不是该树的节点时返回 false
+// 需要从 n 向上找到根节点确认 n 属于该树，时间复杂度 O(log n)
+func (t *RBTree) DeleteNode(n *RBTreeNode) bool {
+	if !t.contains(n) {
+		return false
 	}
 	t.delete(n)
+	t.checkValid()
+	return true
+}
+
+
+// n 是否是树中的节点
+// 已删除的节点 size 为 0；Clear 不修改原有的节点，Clear 前获取的节点和其它树的节点
+// 向上找到的根节点不是 t.root
+func (t *RBTree) contains(n *RBTreeNode) bool {
+	if n == nil || n.size == 0 {
+		return false
+	}
+	for n.parent != nil {
+		n = n.parent
+	}
+	return n == t.root
+}
+
+
+// 获取 k 的所有值，按插入顺序返回
+func (t *RBTree) GetAll(k int) []interface{} {
+	var vs []interface{}
+	for n := t.findNode(RBTreeKey(k)); n != nil && t.compare(n.key, RBTreeKey(k)) == 0; n = n.Next() {
+		vs = append(vs, n.value)
+	}
+	return vs
+}
+
+
+// k 的值的数量
+func (t *RBTree) Count(k int) int {
+	c := 0
+	for n := t.findNode(RBTreeKey(k)); n != nil && t.compare(n.key, RBTreeKey(k)) == 0; n = n.Next() {
+		c++
+	}
+	return c
+}
+
+
+// 删除 k 第一个满足 pred 的值，pred 为 nil 时删除最早插入的值，返回是否删除
+func (t *RBTree) DeleteOne(k int, pred func(v interface{}) bool) bool {
+	for n := t.findNode(RBTreeKey(k)); n != nil && t.compare(n.key, RBTreeKey(k)) == 0; n = n.Next() {
+		if pred == nil || pred(n.value) {
+			t.delete(n)
+			t.checkValid()
+			return true
+		}
+	}
+	return false
 }
 
 
 // 清除树的节点
 func (t *RBTree) Clear() {
-	if t.Root == nil {
+	if t.root == nil {
 		return
 	}else{
-		t.Root = nil
+		t.root = nil
 		t.minNode = nil
 		t.maxNode = nil
-		t.Len = 0
+		t.length = 0
+		t.modCount++
 	}
 }
 
@@ -504,11 +883,11 @@ func (t *RBTree) bfs(layer int) [][]*nodeBox {
 	if layer == 0 {
 		return [][]*nodeBox{}
 	}
-	if t.Root == nil {
+	if t.root == nil {
 		return [][]*nodeBox{ {{c:1}, }}
 	}
 	queue := make([][]*nodeBox, 0)
-	queue = append(queue, []*nodeBox{ {n: t.Root}})
+	queue = append(queue, []*nodeBox{ {n: t.root}})
 	for i := 0; i < layer - 1; i++ {
 		q := queue[i]
 		next := make([]*nodeBox, 0)
@@ -561,11 +940,11 @@ func (t *RBTree) bfs(layer int) [][]*nodeBox {
 //    /--|--\         /--|--\
 // [0x1]   [0x2]   [0x7]   [0x9]
 func (t *RBTree) PrintTree(layer int){
-	if t.Len == 0 {
+	if t.length == 0 {
 		fmt.Println(nil)
 		return
-	}else if t.Len == 1 || layer == 1 {
-		fmt.Printf("[%v]\n", Int64ToHexStr(int64(t.Root.Key)))
+	}else if t.length == 1 || layer == 1 {
+		fmt.Printf("[%v]\n", Int64ToHexStr(int64(t.root.key)))
 		return
 	}
 	var node_width int
@@ -575,9 +954,9 @@ func (t *RBTree) PrintTree(layer int){
 	downLayerMaxNodeCount := 1 << layer // 最底层的节点数量
 	node_additional_width := len("[]")  // 节点额外信息宽度
 	if to16 {
-		node_width = node_additional_width + len(Int64ToHexStr(int64(t.maxNode.Key)))// 显示的节点总宽度
+		node_width = node_additional_width + len(Int64ToHexStr(int64(t.maxNode.key)))// 显示的节点总宽度
 	}else{
-		node_width = node_additional_width + t.maxNode.Key.StrLen() // 显示的节点总宽度
+		node_width = node_additional_width + t.maxNode.key.StrLen() // 显示的节点总宽度
 	}
 	node_width_half := node_width >> 1
 	down_node_interval := node_width - 2 // 最底层节点间的间隔, 即去掉()或[]后的节点宽度
@@ -614,10 +993,10 @@ func (t *RBTree) PrintTree(layer int){
 			if nBox.n != nil {
 				var k string
 				if to16 {
-					k = Int64ToHexStr(int64(nBox.n.Key))
+					k = Int64ToHexStr(int64(nBox.n.key))
 					k = StrRightFilling(k, down_node_interval, " ")
 				}else{
-					k = nBox.n.Key.ToStr()
+					k = nBox.n.key.ToStr()
 					k = StrLeftFilling(k, down_node_interval, "0")
 				}
 				if nBox.n.isBlack() {
@@ -0,0 +1,75 @@
+package grbtree
+
+// 整数 ID 分配器，分配 [min, max] 范围内的 ID
+// 已分配的 ID 保存在 IntervalSet 中，合并成不相邻的区间
+//
+//	a := grbtree.NewIDAllocator(1, 1<<32)
+//	id, err := a.Allocate()
+//	a.Release(id)
+type IDAllocator struct {
+	s   *IntervalSet
+	min RBTreeKey
+	max RBTreeKey
+}
+
+func NewIDAllocator(min, max int64) *IDAllocator {
+	return &IDAllocator{s: NewIntervalSet(), min: RBTreeKey(min), max: RBTreeKey(max)}
+}
+
+// 分配最小的空闲 ID
+func (a *IDAllocator) Allocate() (int64, error) {
+	return a.AllocateRange(1)
+}
+
+// 分配 n 个连续的 ID，返回第一个 ID，使用起点最小的足够大的空闲区间
+func (a *IDAllocator) AllocateRange(n int64) (int64, error) {
+	if n <= 0 || a.max < a.min {
+		return 0, errNoFreeID
+	}
+	lo := a.min
+	for r := a.s.t.minNode; ; r = r.Next() {
+		// 空闲区间 [lo, hi]
+		hi := a.max
+		if r != nil && r.key-1 < hi {
+			hi = r.key - 1
+		}
+		if hi >= lo && uint64(hi-lo) >= uint64(n-1) {
+			a.s.Add(int64(lo), int64(lo)+n-1)
+			return int64(lo), nil
+		}
+		if r == nil || rangeEnd(r) >= a.max {
+			return 0, errNoFreeID
+		}
+		lo = rangeEnd(r) + 1
+	}
+}
+
+// 释放 ID，ID 未分配时返回错误
+func (a *IDAllocator) Release(id int64) error {
+	if !a.s.Contains(id) {
+		return errIDNotAllocated
+	}
+	a.s.Remove(id, id)
+	return nil
+}
+
+// ID 是否已分配
+func (a *IDAllocator) IsAllocated(id int64) bool {
+	return a.s.Contains(id)
+}
+
+// [lo, hi] 范围内的空闲区间，只包含 [min, max] 内可以分配的 ID
+func (a *IDAllocator) FreeRanges(lo, hi int64) []Range {
+	if lo < int64(a.min) {
+		lo = int64(a.min)
+	}
+	if hi > int64(a.max) {
+		hi = int64(a.max)
+	}
+	return a.s.Gaps(lo, hi)
+}
+
+// 已分配的区间数量
+func (a *IDAllocator) RangeCount() int {
+	return a.s.Len()
+}
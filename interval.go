@@ -0,0 +1,140 @@
+package grbtree
+
+// 闭区间 [Lo, Hi]
+type Range struct {
+	Lo int64
+	Hi int64
+}
+
+// 不相交区间的集合，重叠或相邻的区间合并成一个区间
+// 区间保存在树中，key 为区间的起点，值为区间的终点(RBTreeKey)
+//
+//	s := grbtree.NewIntervalSet()
+//	s.Add(0, 99)
+//	s.Add(100, 199) // 合并为 [0, 199]
+//	s.Gaps(0, 999)  // [{200 999}]
+type IntervalSet struct {
+	t *RBTree
+}
+
+func NewIntervalSet() *IntervalSet {
+	return &IntervalSet{t: NewRBTree()}
+}
+
+// 区间的终点
+func rangeEnd(n *RBTreeNode) RBTreeKey {
+	return n.value.(RBTreeKey)
+}
+
+// 第一个与 [lo, ...] 重叠或相邻的区间
+func (s *IntervalSet) firstTouching(lo RBTreeKey) *RBTreeNode {
+	n := s.t.floorNode(lo)
+	if n == nil {
+		return s.t.minNode
+	}
+	if end := rangeEnd(n); end >= lo || end+1 == lo {
+		return n
+	}
+	return n.Next()
+}
+
+// 添加区间 [lo, hi]，与重叠或相邻的区间合并
+func (s *IntervalSet) Add(lo, hi int64) {
+	if lo > hi {
+		return
+	}
+	l, h := RBTreeKey(lo), RBTreeKey(hi)
+	n := s.firstTouching(l)
+	for n != nil && (n.key <= h || h+1 == n.key) {
+		if n.key < l {
+			l = n.key
+		}
+		if end := rangeEnd(n); end > h {
+			h = end
+		}
+		next := n.Next()
+		s.t.delete(n)
+		n = next
+	}
+	s.t.add(l, h)
+}
+
+// 删除区间 [lo, hi]，部分重叠的区间被拆分
+func (s *IntervalSet) Remove(lo, hi int64) {
+	if lo > hi {
+		return
+	}
+	l, h := RBTreeKey(lo), RBTreeKey(hi)
+	n := s.t.floorNode(l)
+	if n == nil {
+		n = s.t.minNode
+	} else if rangeEnd(n) < l {
+		n = n.Next()
+	}
+	for n != nil && n.key <= h {
+		start, end := n.key, rangeEnd(n)
+		next := n.Next()
+		s.t.delete(n)
+		if start < l {
+			s.t.add(start, l-1)
+		}
+		if end > h {
+			s.t.add(h+1, end)
+		}
+		n = next
+	}
+}
+
+// 是否包含 p
+func (s *IntervalSet) Contains(p int64) bool {
+	n := s.t.floorNode(RBTreeKey(p))
+	return n != nil && rangeEnd(n) >= RBTreeKey(p)
+}
+
+// [lo, hi] 是否全部被包含
+func (s *IntervalSet) Covers(lo, hi int64) bool {
+	if lo > hi {
+		return true
+	}
+	n := s.t.floorNode(RBTreeKey(lo))
+	return n != nil && rangeEnd(n) >= RBTreeKey(hi)
+}
+
+// [lo, hi] 范围内没有被包含的区间
+func (s *IntervalSet) Gaps(lo, hi int64) []Range {
+	var ret []Range
+	start, h := RBTreeKey(lo), RBTreeKey(hi)
+	if start > h {
+		return ret
+	}
+	for n := s.firstTouching(start); n != nil && n.key <= h; n = n.Next() {
+		if n.key > start {
+			ret = append(ret, Range{Lo: int64(start), Hi: int64(n.key - 1)})
+		}
+		if rangeEnd(n) >= h {
+			return ret
+		}
+		if rangeEnd(n) >= start {
+			start = rangeEnd(n) + 1
+		}
+	}
+	return append(ret, Range{Lo: int64(start), Hi: hi})
+}
+
+// 按顺序返回所有区间
+func (s *IntervalSet) Ranges() []Range {
+	ret := make([]Range, 0, s.t.Len())
+	for n := s.t.minNode; n != nil; n = n.Next() {
+		ret = append(ret, Range{Lo: int64(n.key), Hi: int64(rangeEnd(n))})
+	}
+	return ret
+}
+
+// 区间的数量
+func (s *IntervalSet) Len() int {
+	return s.t.Len()
+}
+
+func (s *IntervalSet) Clear() {
+	s.t.Clear()
+}
@@ -0,0 +1,77 @@
+package grbtree
+
+import (
+	"encoding/json"
+)
+
+// JSON 中的一个节点
+type jsonEntry struct {
+	Key   RBTreeKey   `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// JSON 中带结构信息的节点
+type jsonNode struct {
+	Key   RBTreeKey   `json:"key"`
+	Value interface{} `json:"value"`
+	Color string      `json:"color"`
+	Left  *jsonNode   `json:"left"`
+	Right *jsonNode   `json:"right"`
+}
+
+// 实现 json.Marshaler，按顺序输出节点数组
+//
+//	[{"key":1,"value":"a"},{"key":2,"value":"b"}]
+func (t *RBTree) MarshalJSON() ([]byte, error) {
+	entries := make([]jsonEntry, 0, t.length)
+	for n := t.minNode; n != nil; n = n.Next() {
+		entries = append(entries, jsonEntry{Key: n.key, Value: n.value})
+	}
+	return json.Marshal(entries)
+}
+
+// 实现 json.Unmarshaler，树原有的节点将被清除
+// 值按 encoding/json 的规则解析为 interface{}，数组有序时以 O(n) 构建树
+func (t *RBTree) UnmarshalJSON(data []byte) error {
+	var entries []jsonEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	nodes := make([]*RBTreeNode, len(entries))
+	for i, e := range entries {
+		nodes[i] = &RBTreeNode{key: e.Key, value: e.Value}
+	}
+	if err := t.buildSorted(nodes); err != errUnsorted {
+		return err
+	}
+	// 数组无序，逐个添加
+	t.Clear()
+	for _, e := range entries {
+		t.add(e.Key, e.Value)
+	}
+	return nil
+}
+
+// 输出树的结构，包含节点的颜色和子节点，用于可视化
+//
+//	{"key":2,"value":"b","color":"black","left":{"key":1,...},"right":null}
+func (t *RBTree) MarshalJSONStructure() ([]byte, error) {
+	return json.Marshal(toJSONNode(t.root))
+}
+
+func toJSONNode(n *RBTreeNode) *jsonNode {
+	if n == nil {
+		return nil
+	}
+	color := "black"
+	if !n.isBlack() {
+		color = "red"
+	}
+	return &jsonNode{
+		Key:   n.key,
+		Value: n.value,
+		Color: color,
+		Left:  toJSONNode(n.left),
+		Right: toJSONNode(n.right),
+	}
+}
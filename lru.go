@@ -0,0 +1,139 @@
+package grbtree
+
+import (
+	"container/list"
+	"sync"
+)
+
+// OrderedLRU 树节点的值
+type lruEntry struct {
+	value interface{}
+	elem  *list.Element // 访问顺序链表中的元素，元素的值为树的节点
+}
+
+// OrderedLRU 的命中统计
+type LRUStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// 有序的 LRU 缓存，可以被多个 goroutine 同时使用
+// 树保存数据，支持按 key 的范围遍历；链表保存访问顺序，超过容量时淘汰最久未访问的节点
+//
+//	c := grbtree.NewOrderedLRU(1000, nil)
+//	c.Add(1, "a")
+//	v, err := c.Get(1)
+//	c.Range(0, 100, func(k grbtree.RBTreeKey, v interface{}) bool { return true })
+type OrderedLRU struct {
+	mu       sync.Mutex
+	t        *RBTree
+	l        *list.List // 头部为最近访问的节点
+	capacity int
+	stats    LRUStats
+	onEvict  func(k RBTreeKey, v interface{})
+}
+
+// onEvict 在节点被淘汰时调用，可以为 nil；capacity 小于 0 时视为 0
+func NewOrderedLRU(capacity int, onEvict func(k RBTreeKey, v interface{})) *OrderedLRU {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &OrderedLRU{
+		t:        NewRBTree(),
+		l:        list.New(),
+		capacity: capacity,
+		onEvict:  onEvict,
+	}
+}
+
+// 添加或替换值，并设为最近访问，超过容量时淘汰最久未访问的节点
+func (c *OrderedLRU) Add(k int, v interface{}) {
+	c.mu.Lock()
+	if n := c.t.findNode(RBTreeKey(k)); n != nil {
+		e := n.value.(*lruEntry)
+		e.value = v
+		c.l.MoveToFront(e.elem)
+		c.mu.Unlock()
+		return
+	}
+	n := c.t.newNode(RBTreeKey(k), nil)
+	n.value = &lruEntry{value: v, elem: c.l.PushFront(n)}
+	c.t.addNode(n)
+
+	var evicted []*RBTreeNode
+	for c.t.Len() > c.capacity {
+		n := c.l.Remove(c.l.Back()).(*RBTreeNode)
+		c.t.delete(n)
+		c.stats.Evictions++
+		evicted = append(evicted, n)
+	}
+	c.mu.Unlock()
+	if c.onEvict != nil {
+		for _, n := range evicted {
+			c.onEvict(n.key, n.value.(*lruEntry).value)
+		}
+	}
+}
+
+// 获取值并设为最近访问
+func (c *OrderedLRU) Get(k int) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := c.t.findNode(RBTreeKey(k))
+	if n == nil {
+		c.stats.Misses++
+		return nil, errKeyNotExists
+	}
+	c.stats.Hits++
+	e := n.value.(*lruEntry)
+	c.l.MoveToFront(e.elem)
+	return e.value, nil
+}
+
+// 获取值，不修改访问顺序和统计
+func (c *OrderedLRU) Peek(k int) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := c.t.findNode(RBTreeKey(k))
+	if n == nil {
+		return nil, errKeyNotExists
+	}
+	return n.value.(*lruEntry).value, nil
+}
+
+func (c *OrderedLRU) Del(k int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := c.t.findNode(RBTreeKey(k))
+	if n == nil {
+		return
+	}
+	c.l.Remove(n.value.(*lruEntry).elem)
+	c.t.delete(n)
+}
+
+// 按 key 的顺序遍历 [lo, hi] 范围内的节点，不修改访问顺序，fn 返回 false 时停止遍历
+// fn 中不能调用 OrderedLRU 的方法
+func (c *OrderedLRU) Range(lo, hi int, fn func(k RBTreeKey, v interface{}) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for n := c.t.lowerBound(RBTreeKey(lo)); n != nil && n.key <= RBTreeKey(hi); n = n.Next() {
+		if !fn(n.key, n.value.(*lruEntry).value) {
+			return
+		}
+	}
+}
+
+func (c *OrderedLRU) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t.Len()
+}
+
+// 命中统计
+func (c *OrderedLRU) Stats() LRUStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
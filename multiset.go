@@ -0,0 +1,105 @@
+package grbtree
+
+// 多重集合，每个 key 只有一个节点，节点的权重即为 key 的数量
+//
+//	s := grbtree.NewMultiSet()
+//	s.Add(10, 3)
+//	s.Count(10) // 3
+type MultiSet struct {
+	t *RBTree
+}
+
+func NewMultiSet() *MultiSet {
+	return &MultiSet{t: NewRBTree()}
+}
+
+// 添加 n 个 k
+func (s *MultiSet) Add(k int, n uint64) {
+	s.add(RBTreeKey(k), n)
+}
+
+func (s *MultiSet) add(k RBTreeKey, n uint64) {
+	if n == 0 {
+		return
+	}
+	if node := s.t.findNode(k); node != nil {
+		node.addSize(n)
+		return
+	}
+	s.t.addNode(&RBTreeNode{key: k, size: n})
+}
+
+// 删除 n 个 k，返回实际删除的数量
+func (s *MultiSet) Remove(k int, n uint64) uint64 {
+	return s.remove(RBTreeKey(k), n)
+}
+
+func (s *MultiSet) remove(k RBTreeKey, n uint64) uint64 {
+	node := s.t.findNode(k)
+	if node == nil || n == 0 {
+		return 0
+	}
+	c := node.weight()
+	if n >= c {
+		s.t.delete(node)
+		return c
+	}
+	node.addSize(-n)
+	return n
+}
+
+// k 的数量
+func (s *MultiSet) Count(k int) uint64 {
+	node := s.t.findNode(RBTreeKey(k))
+	if node == nil {
+		return 0
+	}
+	return node.weight()
+}
+
+// 所有元素的数量(包含重复)
+func (s *MultiSet) Size() uint64 {
+	return s.t.root.subSize()
+}
+
+// 不同 key 的数量
+func (s *MultiSet) Len() int {
+	return int(s.t.length)
+}
+
+func (s *MultiSet) Clear() {
+	s.t.Clear()
+}
+
+// 按从小到大排序后第 i 个(从 0 开始)元素，重复的 key 按数量计算
+func (s *MultiSet) Select(i uint64) (RBTreeKey, error) {
+	node := s.t.selectNode(i)
+	if node == nil {
+		return 0, errIndexOutOfRange
+	}
+	return node.key, nil
+}
+
+// 小于 k 的元素数量，即 k 第一次出现的位置
+func (s *MultiSet) Rank(k int) uint64 {
+	return s.t.rank(RBTreeKey(k))
+}
+
+func (s *MultiSet) Min() (RBTreeKey, error) {
+	k, _, err := s.t.GetMin()
+	return k, err
+}
+
+func (s *MultiSet) Max() (RBTreeKey, error) {
+	k, _, err := s.t.GetMax()
+	return k, err
+}
+
+// 按从小到大的顺序遍历 key 和数量，fn 返回 false 时停止遍历
+func (s *MultiSet) Each(fn func(k RBTreeKey, count uint64) bool) {
+	for n := s.t.minNode; n != nil; n = n.Next() {
+		if !fn(n.key, n.weight()) {
+			return
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package grbtree
+
+// 添加已存在的 key 时的处理方式
+type DuplicatePolicy int
+
+const (
+	DuplicateIgnore  DuplicatePolicy = iota // 忽略新添加的值(默认)
+	DuplicateReplace                        // 用新添加的值替换原先的值
+	DuplicateAllow                          // 多值模式，相同的 key 按插入顺序保存
+)
+
+// NewRBTree 的配置项
+//
+//	desc := func(a, b grbtree.RBTreeKey) int {
+//		if a > b {
+//			return -1
+//		} else if a < b {
+//			return 1
+//		}
+//		return 0
+//	}
+//	tree := grbtree.NewRBTree(
+//		grbtree.WithComparator(desc),
+//		grbtree.WithCapacity(1024),
+//	)
+type Option func(t *RBTree)
+
+// 自定义 key 的比较函数，a < b 返回负数，a == b 返回 0，a > b 返回正数
+// 不要用 int(a - b) 之类的减法实现，key 相差较大时会溢出导致排序错误
+// GetMin/GetMax 返回按比较函数排序后的第一个/最后一个节点
+func WithComparator(cmp func(a, b RBTreeKey) int) Option {
+	return func(t *RBTree) {
+		t.cmp = cmp
+	}
+}
+
+// 添加已存在的 key 时的处理方式
+func WithDuplicatePolicy(p DuplicatePolicy) Option {
+	return func(t *RBTree) {
+		t.dup = p
+	}
+}
+
+// 每次 Add/Del 后检查红黑树的性质，不满足时 panic，用于调试
+func WithValidation(on bool) Option {
+	return func(t *RBTree) {
+		t.validation = on
+	}
+}
+
+// 预分配 n 个节点，添加节点时优先使用预分配的节点
+func WithCapacity(n int) Option {
+	return func(t *RBTree) {
+		if n > 0 {
+			t.pool = make([]RBTreeNode, n)
+		}
+	}
+}
+
+// 序列化时值的编解码方式，默认使用 GobCodec
+func WithValueCodec(c ValueCodec) Option {
+	return func(t *RBTree) {
+		t.codec = c
+	}
+}
+
+// 比较两个 key
+func (t *RBTree) compare(a, b RBTreeKey) int {
+	if t.cmp != nil {
+		return t.cmp(a, b)
+	}
+	return compareKey(a, b)
+}
+
+// 默认的比较函数，按数值从小到大排序
+func compareKey(a, b RBTreeKey) int {
+	if a < b {
+		return -1
+	} else if a > b {
+		return 1
+	}
+	return 0
+}
+
+// 创建权重为 1 的节点，有预分配的节点时使用预分配的节点
+func (t *RBTree) newNode(k RBTreeKey, v interface{}) *RBTreeNode {
+	var n *RBTreeNode
+	if len(t.pool) > 0 {
+		n = &t.pool[0]
+		t.pool = t.pool[1:]
+	} else {
+		n = &RBTreeNode{}
+	}
+	n.key = k
+	n.value = v
+	n.size = 1
+	return n
+}
@@ -0,0 +1,208 @@
+// Package orderbook is an order book implemented by grbtree
+// orderbook 是基于 grbtree 实现的订单簿
+//
+// 买单档位按价格从高到低排序，卖单档位按价格从低到高排序，
+// 两边的最优价格都是树的第一个节点(GetMin)，同一价格档位的订单先进先出
+package orderbook
+
+import (
+	"container/list"
+	"errors"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+var (
+	errOrderExists   = errors.New("OrderAlreadyExists")
+	errOrderNotFound = errors.New("OrderNotFound")
+	errInvalidQty    = errors.New("InvalidQuantity")
+)
+
+type Side int
+
+const (
+	Bid Side = iota // 买
+	Ask             // 卖
+)
+
+// 挂在订单簿中的订单
+type Order struct {
+	ID    uint64
+	Side  Side
+	Price int64
+	Qty   int64 // 剩余数量
+
+	level *priceLevel
+	elem  *list.Element
+}
+
+// 成交记录
+type Fill struct {
+	MakerID uint64 // 订单簿中被成交的订单
+	Price   int64
+	Qty     int64
+}
+
+// 价格档位的快照
+type Level struct {
+	Price  int64
+	Qty    int64
+	Orders int
+}
+
+// 价格档位，订单按时间顺序排列
+type priceLevel struct {
+	node   *grbtree.RBTreeNode // 档位在树中的节点
+	orders *list.List
+	qty    int64
+}
+
+type OrderBook struct {
+	bids   *grbtree.RBTree
+	asks   *grbtree.RBTree
+	orders map[uint64]*Order
+}
+
+func New() *OrderBook {
+	desc := func(a, b grbtree.RBTreeKey) int {
+		if a > b {
+			return -1
+		} else if a < b {
+			return 1
+		}
+		return 0
+	}
+	return &OrderBook{
+		bids:   grbtree.NewRBTree(grbtree.WithComparator(desc)),
+		asks:   grbtree.NewRBTree(),
+		orders: make(map[uint64]*Order),
+	}
+}
+
+func (b *OrderBook) tree(side Side) *grbtree.RBTree {
+	if side == Bid {
+		return b.bids
+	}
+	return b.asks
+}
+
+// 挂单，不与对手方成交；订单号已存在或数量不大于 0 时返回错误
+func (b *OrderBook) Add(id uint64, side Side, price, qty int64) error {
+	if qty <= 0 {
+		return errInvalidQty
+	}
+	if _, ok := b.orders[id]; ok {
+		return errOrderExists
+	}
+	t := b.tree(side)
+	n := t.GetNode(int(price))
+	if n == nil {
+		t.Add(int(price), &priceLevel{orders: list.New()})
+		n = t.GetNode(int(price))
+		n.Value().(*priceLevel).node = n
+	}
+	lv := n.Value().(*priceLevel)
+	o := &Order{ID: id, Side: side, Price: price, Qty: qty, level: lv}
+	o.elem = lv.orders.PushBack(o)
+	lv.qty += qty
+	b.orders[id] = o
+	return nil
+}
+
+// 撤单
+func (b *OrderBook) Cancel(id uint64) error {
+	o, ok := b.orders[id]
+	if !ok {
+		return errOrderNotFound
+	}
+	b.remove(o)
+	return nil
+}
+
+// 从订单簿中删除订单，档位没有订单时删除档位
+func (b *OrderBook) remove(o *Order) {
+	lv := o.level
+	lv.orders.Remove(o.elem)
+	lv.qty -= o.Qty
+	delete(b.orders, o.ID)
+	if lv.orders.Len() == 0 {
+		b.tree(o.Side).DeleteNode(lv.node)
+	}
+}
+
+// 以 side 方向、限价 price 吃掉对手方最多 qty 数量的订单，返回成交记录和未成交的数量
+// 按价格优先、时间优先成交，成交价为对手方订单的价格
+func (b *OrderBook) Match(side Side, price, qty int64) ([]Fill, int64) {
+	var fills []Fill
+	t := b.asks
+	if side == Ask {
+		t = b.bids
+	}
+	for qty > 0 {
+		n := t.MinNode()
+		if n == nil {
+			break
+		}
+		if best := int64(n.Key()); side == Bid && best > price || side == Ask && best < price {
+			break
+		}
+		lv := n.Value().(*priceLevel)
+		for qty > 0 && lv.orders.Len() > 0 {
+			o := lv.orders.Front().Value.(*Order)
+			q := o.Qty
+			if q > qty {
+				q = qty
+			}
+			fills = append(fills, Fill{MakerID: o.ID, Price: o.Price, Qty: q})
+			qty -= q
+			if q == o.Qty {
+				b.remove(o)
+			} else {
+				o.Qty -= q
+				lv.qty -= q
+			}
+		}
+	}
+	return fills, qty
+}
+
+// 最优买价
+func (b *OrderBook) BestBid() (price, qty int64, ok bool) {
+	return best(b.bids)
+}
+
+// 最优卖价
+func (b *OrderBook) BestAsk() (price, qty int64, ok bool) {
+	return best(b.asks)
+}
+
+func best(t *grbtree.RBTree) (int64, int64, bool) {
+	k, v, err := t.GetMin()
+	if err != nil {
+		return 0, 0, false
+	}
+	return int64(k), v.(*priceLevel).qty, true
+}
+
+// 双方最优的 n 个价格档位，按从优到劣排列
+func (b *OrderBook) Depth(n int) (bids, asks []Level) {
+	return depth(b.bids, n), depth(b.asks, n)
+}
+
+func depth(t *grbtree.RBTree, n int) []Level {
+	var ret []Level
+	for node := t.MinNode(); node != nil && len(ret) < n; node = node.Next() {
+		lv := node.Value().(*priceLevel)
+		ret = append(ret, Level{Price: int64(node.Key()), Qty: lv.qty, Orders: lv.orders.Len()})
+	}
+	return ret
+}
+
+// 查询订单
+func (b *OrderBook) Order(id uint64) (Order, bool) {
+	o, ok := b.orders[id]
+	if !ok {
+		return Order{}, false
+	}
+	return *o, true
+}
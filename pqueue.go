@@ -0,0 +1,81 @@
+package grbtree
+
+// 优先队列中的元素，作为句柄用于修改优先级或删除
+type PQItem struct {
+	n     *RBTreeNode    // 元素在树中的节点，节点的值为元素本身
+	q     *PriorityQueue // 元素所属的队列
+	value interface{}
+}
+
+// 元素的优先级
+func (it *PQItem) Priority() RBTreeKey {
+	return it.n.key
+}
+
+func (it *PQItem) Value() interface{} {
+	return it.value
+}
+
+// 优先队列，优先级数值小的先出队，优先级相同时按插入顺序出队
+// 基于多值模式的树，相同的 key 按插入顺序排列
+//
+//	q := grbtree.NewPriorityQueue()
+//	a := q.Push(5, "a")
+//	q.Push(3, "b")
+//	q.Update(a, 1)
+//	it, _ := q.PopMin() // a
+type PriorityQueue struct {
+	t *RBTree
+}
+
+func NewPriorityQueue() *PriorityQueue {
+	return &PriorityQueue{t: NewMultiRBTree()}
+}
+
+// 添加元素，返回元素的句柄
+func (q *PriorityQueue) Push(priority int, v interface{}) *PQItem {
+	it := &PQItem{q: q, value: v}
+	q.push(it, RBTreeKey(priority))
+	return it
+}
+
+func (q *PriorityQueue) push(it *PQItem, priority RBTreeKey) {
+	it.n = q.t.newNode(priority, it)
+	q.t.addNode(it.n)
+}
+
+// 修改元素的优先级，修改后视为新插入的元素，元素已不在队列中或属于其它队列时返回 false
+func (q *PriorityQueue) Update(it *PQItem, priority int) bool {
+	if it.q != q || !q.t.DeleteNode(it.n) {
+		return false
+	}
+	q.push(it, RBTreeKey(priority))
+	return true
+}
+
+// 删除元素，元素已不在队列中或属于其它队列时返回 false
+func (q *PriorityQueue) Remove(it *PQItem) bool {
+	return it.q == q && q.t.DeleteNode(it.n)
+}
+
+// 删除并返回优先级最小的元素
+func (q *PriorityQueue) PopMin() (*PQItem, error) {
+	_, v, err := q.t.PopMin()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*PQItem), nil
+}
+
+// 返回优先级最小的元素，不删除
+func (q *PriorityQueue) PeekMin() (*PQItem, error) {
+	_, v, err := q.t.GetMin()
+	if err != nil {
+		return nil, err
+	}
+	return v.(*PQItem), nil
+}
+
+func (q *PriorityQueue) Len() int {
+	return q.t.Len()
+}
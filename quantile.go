@@ -0,0 +1,63 @@
+package grbtree
+
+import "math"
+
+// 滑动窗口的分位数，保存最近 size 个样本
+// 样本保存在 MultiSet 中，Quantile 通过按权重的顺序统计查找，O(log n)
+//
+//	wq := grbtree.NewWindowQuantile(1000)
+//	wq.Push(latency)
+//	p95, _ := wq.Quantile(0.95)
+type WindowQuantile struct {
+	s       *MultiSet
+	samples []int64 // 环形缓冲区，按加入顺序保存窗口内的样本
+	next    int     // 下一个样本在缓冲区中的位置
+	count   int
+}
+
+func NewWindowQuantile(size int) *WindowQuantile {
+	if size <= 0 {
+		size = 1
+	}
+	return &WindowQuantile{s: NewMultiSet(), samples: make([]int64, size)}
+}
+
+// 加入样本，窗口已满时删除最早的样本
+func (w *WindowQuantile) Push(sample int64) {
+	if w.count == len(w.samples) {
+		w.s.remove(RBTreeKey(w.samples[w.next]), 1)
+	} else {
+		w.count++
+	}
+	w.samples[w.next] = sample
+	w.next = (w.next + 1) % len(w.samples)
+	w.s.add(RBTreeKey(sample), 1)
+}
+
+// q 分位数(0 <= q <= 1)，使用最近秩方法：排序后第 ceil(q*n) 个样本
+func (w *WindowQuantile) Quantile(q float64) (int64, error) {
+	if w.count == 0 {
+		return 0, errNotNode
+	}
+	if q < 0 || q > 1 || math.IsNaN(q) {
+		return 0, errIndexOutOfRange
+	}
+	i := int(math.Ceil(q*float64(w.count))) - 1
+	if i < 0 {
+		i = 0
+	}
+	k, err := w.s.Select(uint64(i))
+	return int64(k), err
+}
+
+// 窗口内的样本数量
+func (w *WindowQuantile) Len() int {
+	return w.count
+}
+
+// 清除所有样本
+func (w *WindowQuantile) Reset() {
+	w.s.Clear()
+	w.next = 0
+	w.count = 0
+}
@@ -0,0 +1,91 @@
+package grbtree
+
+// 最后一个 key <= k 的节点，多值模式下为 k 最后插入的节点
+func (t *RBTree) floorNode(k RBTreeKey) *RBTreeNode {
+	var ret *RBTreeNode
+	n := t.root
+	for n != nil {
+		if t.compare(n.key, k) <= 0 {
+			ret = n
+			n = n.right
+		} else {
+			n = n.left
+		}
+	}
+	return ret
+}
+
+// 小于等于 k 的最大节点
+func (t *RBTree) Floor(k int) (RBTreeKey, interface{}, error) {
+	n := t.floorNode(RBTreeKey(k))
+	if n == nil {
+		return 0, nil, errKeyNotExists
+	}
+	return n.key, n.value, nil
+}
+
+// 大于等于 k 的最小节点
+func (t *RBTree) Ceiling(k int) (RBTreeKey, interface{}, error) {
+	n := t.lowerBound(RBTreeKey(k))
+	if n == nil {
+		return 0, nil, errKeyNotExists
+	}
+	return n.key, n.value, nil
+}
+
+// k 两侧最接近的节点，k 存在时两个返回值都是 k 的节点
+func (t *RBTree) neighbors(k RBTreeKey) (lo, hi *RBTreeNode) {
+	n, recent := t.findNodeAndRecentNode(k)
+	if n != nil {
+		return n, n
+	}
+	if recent == nil {
+		return nil, nil
+	}
+	// 查找路径的最后一个节点即为 k 的前驱或后继节点
+	if t.compare(k, recent.key) < 0 {
+		return recent.Prev(), recent
+	}
+	return recent, recent.Next()
+}
+
+// key 与 k 的距离
+func keyDistance(a, b RBTreeKey) uint64 {
+	if a >= b {
+		return uint64(a) - uint64(b)
+	}
+	return uint64(b) - uint64(a)
+}
+
+// 与 k 数值最接近的节点，距离相同时返回较小的节点
+func (t *RBTree) Nearest(k int) (RBTreeKey, interface{}, error) {
+	ns := t.KNearest(k, 1)
+	if len(ns) == 0 {
+		return 0, nil, errNotNode
+	}
+	return ns[0].key, ns[0].value, nil
+}
+
+// 与 k 数值最接近的 n 个节点，按距离从近到远排列，距离相同时较小的节点在前
+// 从 k 两侧最接近的节点开始，分别向前驱和后继方向扩展
+func (t *RBTree) KNearest(k int, n int) []*RBTreeNode {
+	if n <= 0 {
+		return nil
+	}
+	key := RBTreeKey(k)
+	lo, hi := t.neighbors(key)
+	if lo != nil && lo == hi {
+		hi = hi.Next()
+	}
+	ret := make([]*RBTreeNode, 0, n)
+	for len(ret) < n && (lo != nil || hi != nil) {
+		if hi == nil || lo != nil && keyDistance(lo.key, key) <= keyDistance(hi.key, key) {
+			ret = append(ret, lo)
+			lo = lo.Prev()
+		} else {
+			ret = append(ret, hi)
+			hi = hi.Next()
+		}
+	}
+	return ret
+}
@@ -0,0 +1,134 @@
+package grbtree
+
+// 按位置排序的序列，在任意位置插入、删除、访问都是 O(log n)
+// 基于红黑树，节点的 key 不使用，通过子树的节点数量(size)确定节点的位置，
+// 插入、删除复用树的旋转和调整操作
+//
+//	s := grbtree.NewSequence[string]()
+//	s.Push("a")
+//	s.InsertAt(0, "b") // [b a]
+//	s.At(1)            // a
+type Sequence[V any] struct {
+	t *RBTree
+}
+
+func NewSequence[V any](vs ...V) *Sequence[V] {
+	// 所有节点的 key 相同，使用多值模式使 Validate 的顺序检查成立
+	s := &Sequence[V]{t: NewMultiRBTree()}
+	for _, v := range vs {
+		s.Push(v)
+	}
+	return s
+}
+
+func (s *Sequence[V]) Len() int {
+	return s.t.Len()
+}
+
+// 在位置 i 插入 v，i 的范围为 [0, Len]
+func (s *Sequence[V]) InsertAt(i int, v V) error {
+	if i < 0 || i > s.t.Len() {
+		return errIndexOutOfRange
+	}
+	n := s.t.newNode(0, v)
+	if s.t.root == nil {
+		return s.t.addNode(n)
+	}
+	if i == s.t.Len() {
+		s.t.link(s.t.maxNode, n, false)
+		return nil
+	}
+	// 插入到原先位置 i 的节点之前
+	at := s.t.selectNode(uint64(i))
+	if at.left == nil {
+		s.t.link(at, n, true)
+		return nil
+	}
+	p := at.left
+	for p.right != nil {
+		p = p.right
+	}
+	s.t.link(p, n, false)
+	return nil
+}
+
+// 添加到末尾
+func (s *Sequence[V]) Push(v V) {
+	s.InsertAt(s.t.Len(), v)
+}
+
+// 删除位置 i 的元素并返回
+func (s *Sequence[V]) DeleteAt(i int) (V, error) {
+	var v V
+	n := s.node(i)
+	if n == nil {
+		return v, errIndexOutOfRange
+	}
+	s.t.delete(n)
+	return seqValue[V](n), nil
+}
+
+func (s *Sequence[V]) node(i int) *RBTreeNode {
+	if i < 0 || i >= s.t.Len() {
+		return nil
+	}
+	return s.t.selectNode(uint64(i))
+}
+
+// 位置 i 的元素
+func (s *Sequence[V]) At(i int) (V, error) {
+	var v V
+	n := s.node(i)
+	if n == nil {
+		return v, errIndexOutOfRange
+	}
+	return seqValue[V](n), nil
+}
+
+// 修改位置 i 的元素
+func (s *Sequence[V]) Set(i int, v V) error {
+	n := s.node(i)
+	if n == nil {
+		return errIndexOutOfRange
+	}
+	n.value = v
+	return nil
+}
+
+// [i, j) 范围内的元素
+func (s *Sequence[V]) Slice(i, j int) ([]V, error) {
+	if i < 0 || j > s.t.Len() || i > j {
+		return nil, errIndexOutOfRange
+	}
+	ret := make([]V, 0, j-i)
+	for n := s.node(i); len(ret) < j-i; n = n.Next() {
+		ret = append(ret, seqValue[V](n))
+	}
+	return ret, nil
+}
+
+// 把 o 的所有元素按顺序添加到末尾，o 不变
+func (s *Sequence[V]) Concat(o *Sequence[V]) {
+	// 先取出 o 的元素，o 与 s 相同时不会无限添加
+	vs, _ := o.Slice(0, o.Len())
+	for _, v := range vs {
+		s.Push(v)
+	}
+}
+
+// 按顺序遍历元素，fn 返回 false 时停止遍历
+func (s *Sequence[V]) Each(fn func(i int, v V) bool) {
+	i := 0
+	for n := s.t.minNode; n != nil; n = n.Next() {
+		if !fn(i, seqValue[V](n)) {
+			return
+		}
+		i++
+	}
+}
+
+// 节点的值，V 为接口类型时值可能为 nil
+func seqValue[V any](n *RBTreeNode) V {
+	v, _ := n.value.(V)
+	return v
+}
@@ -0,0 +1,391 @@
+package grbtree
+
+import "math/bits"
+
+// Set 可用作 key 的整数类型, 需要能无损转换为 RBTreeKey
+type SetKey interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint8 | ~uint16 | ~uint32
+}
+
+// Set 的节点，只保存 key、颜色和指针，没有 RBTreeNode 的值和子树权重
+type setNode[K SetKey] struct {
+	key    K
+	red    bool
+	parent *setNode[K]
+	left   *setNode[K]
+	right  *setNode[K]
+}
+
+// 有序集合，基于红黑树实现，节点不保存值
+//
+//	s := grbtree.NewSet[int](3, 1, 2)
+//	s.Insert(4)
+//	s.Each(func(k int) bool { fmt.Println(k); return true })
+type Set[K SetKey] struct {
+	root   *setNode[K]
+	length int
+}
+
+func NewSet[K SetKey](ks ...K) *Set[K] {
+	s := &Set[K]{}
+	for _, k := range ks {
+		s.Insert(k)
+	}
+	return s
+}
+
+func (n *setNode[K]) isRed() bool {
+	return n != nil && n.red
+}
+
+// 中序遍历的下一个节点
+func (n *setNode[K]) next() *setNode[K] {
+	if n.right != nil {
+		n = n.right
+		for n.left != nil {
+			n = n.left
+		}
+		return n
+	}
+	for n.parent != nil && n == n.parent.right {
+		n = n.parent
+	}
+	return n.parent
+}
+
+func (s *Set[K]) first() *setNode[K] {
+	n := s.root
+	for n != nil && n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func (s *Set[K]) last() *setNode[K] {
+	n := s.root
+	for n != nil && n.right != nil {
+		n = n.right
+	}
+	return n
+}
+
+func (s *Set[K]) find(k K) *setNode[K] {
+	n := s.root
+	for n != nil {
+		if k < n.key {
+			n = n.left
+		} else if k > n.key {
+			n = n.right
+		} else {
+			return n
+		}
+	}
+	return nil
+}
+
+// 用 n 替换 old 在父节点中的位置，n 可以为 nil
+func (s *Set[K]) replace(old, n *setNode[K]) {
+	p := old.parent
+	if p == nil {
+		s.root = n
+	} else if p.left == old {
+		p.left = n
+	} else {
+		p.right = n
+	}
+	if n != nil {
+		n.parent = p
+	}
+}
+
+func (s *Set[K]) leftRotate(n *setNode[K]) {
+	r := n.right
+	n.right = r.left
+	if r.left != nil {
+		r.left.parent = n
+	}
+	s.replace(n, r)
+	r.left = n
+	n.parent = r
+}
+
+func (s *Set[K]) rightRotate(n *setNode[K]) {
+	l := n.left
+	n.left = l.right
+	if l.right != nil {
+		l.right.parent = n
+	}
+	s.replace(n, l)
+	l.right = n
+	n.parent = l
+}
+
+// 添加元素，元素已存在时返回 false
+func (s *Set[K]) Insert(k K) bool {
+	var p *setNode[K]
+	for n := s.root; n != nil; {
+		p = n
+		if k < n.key {
+			n = n.left
+		} else if k > n.key {
+			n = n.right
+		} else {
+			return false
+		}
+	}
+	n := &setNode[K]{key: k, red: true, parent: p}
+	if p == nil {
+		s.root = n
+	} else if k < p.key {
+		p.left = n
+	} else {
+		p.right = n
+	}
+	s.length++
+	s.insertFixUp(n)
+	return true
+}
+
+// 添加节点后的调整，与 RBTree.insertFixUp 相同
+func (s *Set[K]) insertFixUp(n *setNode[K]) {
+	for n.parent.isRed() {
+		// 父节点为红色，一定不是根节点，祖父节点存在
+		p := n.parent
+		g := p.parent
+		if p == g.left {
+			if u := g.right; u.isRed() {
+				p.red, u.red, g.red = false, false, true
+				n = g
+				continue
+			}
+			if n == p.right {
+				n = p
+				s.leftRotate(n)
+				p = n.parent
+			}
+			p.red, g.red = false, true
+			s.rightRotate(g)
+		} else {
+			if u := g.left; u.isRed() {
+				p.red, u.red, g.red = false, false, true
+				n = g
+				continue
+			}
+			if n == p.left {
+				n = p
+				s.rightRotate(n)
+				p = n.parent
+			}
+			p.red, g.red = false, true
+			s.leftRotate(g)
+		}
+	}
+	s.root.red = false
+}
+
+// 是否包含元素
+func (s *Set[K]) Contains(k K) bool {
+	return s.find(k) != nil
+}
+
+// 删除元素，元素不存在时返回 false
+func (s *Set[K]) Remove(k K) bool {
+	n := s.find(k)
+	if n == nil {
+		return false
+	}
+	s.delete(n)
+	return true
+}
+
+func (s *Set[K]) delete(n *setNode[K]) {
+	// x 为移到被删除位置的节点(可能为 nil)，xp 为 x 的父节点
+	var x, xp *setNode[K]
+	removedRed := n.red
+	if n.left == nil {
+		x, xp = n.right, n.parent
+		s.replace(n, n.right)
+	} else if n.right == nil {
+		x, xp = n.left, n.parent
+		s.replace(n, n.left)
+	} else {
+		// 用后继节点(右子树的最左节点)代替 n
+		next := n.right
+		for next.left != nil {
+			next = next.left
+		}
+		removedRed = next.red
+		x = next.right
+		if next.parent == n {
+			xp = next
+		} else {
+			xp = next.parent
+			s.replace(next, next.right)
+			next.right = n.right
+			next.right.parent = next
+		}
+		s.replace(n, next)
+		next.left = n.left
+		next.left.parent = next
+		next.red = n.red
+	}
+	n.parent, n.left, n.right = nil, nil, nil
+	s.length--
+	if !removedRed {
+		s.deleteFixUp(x, xp)
+	}
+}
+
+// 删除黑色节点后的调整，x 所在的路径少了一个黑色节点
+func (s *Set[K]) deleteFixUp(x, p *setNode[K]) {
+	for x != s.root && !x.isRed() {
+		if x == p.left {
+			b := p.right
+			if b.isRed() {
+				b.red, p.red = false, true
+				s.leftRotate(p)
+				b = p.right
+			}
+			if !b.left.isRed() && !b.right.isRed() {
+				b.red = true
+				x, p = p, p.parent
+				continue
+			}
+			if !b.right.isRed() {
+				b.left.red, b.red = false, true
+				s.rightRotate(b)
+				b = p.right
+			}
+			b.red, p.red, b.right.red = p.red, false, false
+			s.leftRotate(p)
+			x = s.root
+		} else {
+			b := p.left
+			if b.isRed() {
+				b.red, p.red = false, true
+				s.rightRotate(p)
+				b = p.left
+			}
+			if !b.left.isRed() && !b.right.isRed() {
+				b.red = true
+				x, p = p, p.parent
+				continue
+			}
+			if !b.left.isRed() {
+				b.right.red, b.red = false, true
+				s.leftRotate(b)
+				b = p.left
+			}
+			b.red, p.red, b.left.red = p.red, false, false
+			s.rightRotate(p)
+			x = s.root
+		}
+	}
+	if x != nil {
+		x.red = false
+	}
+}
+
+// 元素数量
+func (s *Set[K]) Len() int {
+	return s.length
+}
+
+func (s *Set[K]) Clear() {
+	s.root = nil
+	s.length = 0
+}
+
+func (s *Set[K]) Min() (K, error) {
+	n := s.first()
+	if n == nil {
+		return 0, errNotNode
+	}
+	return n.key, nil
+}
+
+func (s *Set[K]) Max() (K, error) {
+	n := s.last()
+	if n == nil {
+		return 0, errNotNode
+	}
+	return n.key, nil
+}
+
+// 按从小到大的顺序遍历元素，fn 返回 false 时停止遍历
+func (s *Set[K]) Each(fn func(k K) bool) {
+	for n := s.first(); n != nil; n = n.next() {
+		if !fn(n.key) {
+			return
+		}
+	}
+}
+
+// 按从小到大的顺序返回所有元素
+func (s *Set[K]) Keys() []K {
+	ks := make([]K, 0, s.length)
+	for n := s.first(); n != nil; n = n.next() {
+		ks = append(ks, n.key)
+	}
+	return ks
+}
+
+// 并集
+func (s *Set[K]) Union(o *Set[K]) *Set[K] {
+	return s.merge(o, true, true, true)
+}
+
+// 交集
+func (s *Set[K]) Intersect(o *Set[K]) *Set[K] {
+	return s.merge(o, false, true, false)
+}
+
+// 差集，s 中有而 o 中没有的元素
+func (s *Set[K]) Difference(o *Set[K]) *Set[K] {
+	return s.merge(o, true, false, false)
+}
+
+// 同时按顺序遍历两个集合生成新集合，onlyS、both、onlyO 分别表示是否保留
+// 只在 s 中、两个集合都有、只在 o 中的元素
+// 得到的元素已经有序，直接构建平衡的树，时间复杂度 O(n)
+func (s *Set[K]) merge(o *Set[K], onlyS, both, onlyO bool) *Set[K] {
+	var ks []K
+	a, b := s.first(), o.first()
+	for a != nil || b != nil {
+		switch {
+		case b == nil || a != nil && a.key < b.key:
+			if onlyS {
+				ks = append(ks, a.key)
+			}
+			a = a.next()
+		case a == nil || b.key < a.key:
+			if onlyO {
+				ks = append(ks, b.key)
+			}
+			b = b.next()
+		default:
+			if both {
+				ks = append(ks, a.key)
+			}
+			a, b = a.next(), b.next()
+		}
+	}
+	ret := &Set[K]{length: len(ks)}
+	if len(ks) > 0 {
+		// 与 RBTree.buildSorted 相同，最深一层的节点设为红色
+		ret.root = buildSetBalanced(ks, nil, 0, bits.Len(uint(len(ks)))-1)
+		ret.root.red = false
+	}
+	return ret
+}
+
+func buildSetBalanced[K SetKey](ks []K, parent *setNode[K], depth, redDepth int) *setNode[K] {
+	if len(ks) == 0 {
+		return nil
+	}
+	mid := len(ks) / 2
+	n := &setNode[K]{key: ks[mid], red: depth == redDepth, parent: parent}
+	n.left = buildSetBalanced(ks[:mid], n, depth+1, redDepth)
+	n.right = buildSetBalanced(ks[mid+1:], n, depth+1, redDepth)
+	return n
+}
@@ -0,0 +1,213 @@
+package grbtree
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// 流式格式:
+//
+//	magic "GRBS" | version(1 byte) | flags(1 byte)
+//	以下部分在 flags 包含 streamGzip 时使用 gzip 压缩:
+//	每个节点: 1(1 byte) | key(varint) | 值长度(uvarint) | 值
+//	结束标记 0(1 byte) | crc32(IEEE, 4 bytes 大端) 校验所有节点和结束标记
+const (
+	streamMagic   = "GRBS"
+	streamVersion = 1
+
+	streamGzip = 1 << 0
+)
+
+// Encode 的配置项
+type EncodeOption func(c *encodeConfig)
+
+type encodeConfig struct {
+	gzip  bool
+	level int
+}
+
+// 使用 gzip 压缩，level 为 compress/gzip 的压缩级别
+func WithGzip(level int) EncodeOption {
+	return func(c *encodeConfig) {
+		c.gzip = true
+		c.level = level
+	}
+}
+
+// 按顺序把节点逐个写入 w，不会把整棵树编码到一个缓冲区中
+// 值使用 WithValueCodec 设置的编解码
+func (t *RBTree) Encode(w io.Writer, opts ...EncodeOption) error {
+	var conf encodeConfig
+	for _, opt := range opts {
+		opt(&conf)
+	}
+	var flags byte
+	if conf.gzip {
+		flags |= streamGzip
+	}
+	header := append([]byte(streamMagic), streamVersion, flags)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	var zw *gzip.Writer
+	if conf.gzip {
+		var err error
+		if zw, err = gzip.NewWriterLevel(w, conf.level); err != nil {
+			return err
+		}
+		w = zw
+	}
+	bw := bufio.NewWriter(w)
+	crc := crc32.NewIEEE()
+	rw := io.MultiWriter(bw, crc)
+	codec := t.valueCodec()
+	var buf [1 + 2*binary.MaxVarintLen64]byte
+	for n := t.minNode; n != nil; n = n.Next() {
+		v, err := codec.EncodeValue(n.value)
+		if err != nil {
+			return err
+		}
+		buf[0] = 1
+		l := 1 + binary.PutVarint(buf[1:], int64(n.key))
+		l += binary.PutUvarint(buf[l:], uint64(len(v)))
+		if _, err := rw.Write(buf[:l]); err != nil {
+			return err
+		}
+		if _, err := rw.Write(v); err != nil {
+			return err
+		}
+	}
+	if _, err := rw.Write([]byte{0}); err != nil {
+		return err
+	}
+	binary.BigEndian.PutUint32(buf[:4], crc.Sum32())
+	if _, err := bw.Write(buf[:4]); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if zw != nil {
+		return zw.Close()
+	}
+	return nil
+}
+
+// 可以逐字节读取的 Reader
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// 读取时计算 crc
+type crcReader struct {
+	r   byteReader
+	crc hash.Hash32
+}
+
+func (r *crcReader) ReadByte() (byte, error) {
+	b, err := r.r.ReadByte()
+	if err == nil {
+		r.crc.Write([]byte{b})
+	}
+	return b, err
+}
+
+func (r *crcReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.crc.Write(p[:n])
+	return n, err
+}
+
+// 从 r 逐个读取 Encode 写入的节点，树原有的节点将被替换
+// 节点已经有序，逐个添加为新树的最后一个节点，不需要额外保存所有节点；
+// 全部节点读取并校验成功后才用新树替换树的节点，出错时树保持不变，与 UnmarshalBinary 一致
+// r 实现了 io.ByteReader 时只读取到树的数据结束为止，r 中之后的数据可以继续读取；
+// 否则会用 bufio.Reader 包装 r，可能读取超过树的数据
+func (t *RBTree) Decode(r io.Reader) error {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	nt := &RBTree{cmp: t.cmp, dup: t.dup, codec: t.codec}
+	if err := nt.decode(br); err != nil {
+		return err
+	}
+	t.root, t.minNode, t.maxNode, t.length = nt.root, nt.minNode, nt.maxNode, nt.length
+	t.modCount++
+	return nil
+}
+
+func (t *RBTree) decode(r byteReader) error {
+	header := make([]byte, len(streamMagic)+2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return errBadFormat
+	}
+	if string(header[:len(streamMagic)]) != streamMagic {
+		return errBadFormat
+	}
+	if header[len(streamMagic)] != streamVersion {
+		return errBadVersion
+	}
+	compressed := header[len(streamMagic)+1]&streamGzip != 0
+	if compressed {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		// 只读取一个 gzip 成员，不读取之后的数据
+		zr.Multistream(false)
+		r = bufio.NewReader(zr)
+	}
+	cr := &crcReader{r: r, crc: crc32.NewIEEE()}
+	codec := t.valueCodec()
+	for {
+		mark, err := cr.ReadByte()
+		if err != nil {
+			return errBadFormat
+		}
+		if mark == 0 {
+			break
+		}
+		k, err := binary.ReadVarint(cr)
+		if err != nil {
+			return errBadFormat
+		}
+		vl, err := binary.ReadUvarint(cr)
+		if err != nil {
+			return errBadFormat
+		}
+		// 按实际读取的数据分配内存，避免错误的长度导致分配过大的内存
+		b, err := io.ReadAll(io.LimitReader(cr, int64(vl)))
+		if err != nil || uint64(len(b)) != vl {
+			return errBadFormat
+		}
+		v, err := codec.DecodeValue(b)
+		if err != nil {
+			return err
+		}
+		if err := t.pushBack(t.newNode(RBTreeKey(k), v)); err != nil {
+			return err
+		}
+	}
+	sum := make([]byte, 4)
+	if _, err := io.ReadFull(r, sum); err != nil {
+		return errBadFormat
+	}
+	if binary.BigEndian.Uint32(sum) != cr.crc.Sum32() {
+		return errBadChecksum
+	}
+	if compressed {
+		// 读取 gzip 的结尾，同时校验 gzip 自身的 crc
+		if _, err := r.ReadByte(); err != io.EOF {
+			return errBadFormat
+		}
+	}
+	return nil
+}
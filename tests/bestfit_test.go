@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestBestFit(t *testing.T) {
+	a := grbtree.NewBestFit(0, 100)
+	x, _ := a.Allocate(30) // [0, 30)
+	y, _ := a.Allocate(10) // [30, 40)
+	z, _ := a.Allocate(20) // [40, 60)
+	if x != 0 || y != 30 || z != 40 {
+		t.Fatalf("Allocate %d %d %d", x, y, z)
+	}
+	a.Free(y)
+	// 空闲块 [30, 40) 和 [60, 100)，8 使用最接近的 [30, 40)
+	if addr, _ := a.Allocate(8); addr != 30 {
+		t.Fatalf("best fit %d", addr)
+	}
+	if addr, _ := a.Allocate(15); addr != 60 {
+		t.Fatalf("best fit %d", addr)
+	}
+	if _, err := a.Allocate(50); err == nil {
+		t.Fatal("Allocate larger than free block")
+	}
+	if err := a.Free(12345); err == nil {
+		t.Fatal("Free unknown address")
+	}
+	a.Free(x)
+	a.Free(z)
+	a.Free(30)
+	a.Free(60)
+	if a.FreeBlocks() != 1 || a.LargestFree() != 100 || a.FreeBytes() != 100 {
+		t.Fatalf("blocks %d largest %d", a.FreeBlocks(), a.LargestFree())
+	}
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	tree := grbtree.NewRBTree()
+	for i := 0; i < 100; i++ {
+		tree.Add(i*7%101, "v"+strconv.Itoa(i))
+	}
+	data, err := tree.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := grbtree.NewRBTree()
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := loaded.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Len() != tree.Len() {
+		t.Fatalf("Len %d", loaded.Len())
+	}
+	for i := 0; i < 100; i++ {
+		v, err := loaded.Get(i * 7 % 101)
+		if err != nil || v.(string) != "v"+strconv.Itoa(i) {
+			t.Fatalf("Get %d: %v %v", i, v, err)
+		}
+	}
+}
+
+type stringCodec struct{}
+
+func (stringCodec) EncodeValue(v interface{}) ([]byte, error) {
+	return []byte(v.(string)), nil
+}
+
+func (stringCodec) DecodeValue(b []byte) (interface{}, error) {
+	return string(b), nil
+}
+
+func TestMarshalBinaryCodec(t *testing.T) {
+	tree := grbtree.NewRBTree(grbtree.WithValueCodec(stringCodec{}))
+	tree.Add(1, "a")
+	tree.Add(2, "b")
+	data, err := tree.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := grbtree.NewRBTree(grbtree.WithValueCodec(stringCodec{}))
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := loaded.Get(2); v.(string) != "b" {
+		t.Fatalf("Get %v", v)
+	}
+
+	data[len(data)-5] ^= 0xff
+	if err := loaded.UnmarshalBinary(data); err == nil {
+		t.Fatal("checksum not verified")
+	}
+}
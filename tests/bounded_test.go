@@ -0,0 +1,46 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestBoundedTree(t *testing.T) {
+	var evicted []grbtree.RBTreeKey
+	bt := grbtree.NewBoundedTree(3, grbtree.EvictMin, func(k grbtree.RBTreeKey, v interface{}) {
+		evicted = append(evicted, k)
+	})
+	for _, k := range []int{5, 1, 9, 7, 3, 8} {
+		bt.Add(k, k)
+	}
+	var ks []grbtree.RBTreeKey
+	for c := bt.Cursor(); c.Next(); {
+		ks = append(ks, c.Key())
+	}
+	if bt.Len() != 3 || len(ks) != 3 || ks[0] != 7 || ks[1] != 8 || ks[2] != 9 {
+		t.Fatalf("keys %v", ks)
+	}
+	if len(evicted) != 3 || evicted[0] != 1 || evicted[1] != 3 || evicted[2] != 5 {
+		t.Fatalf("evicted %v", evicted)
+	}
+
+	bt = grbtree.NewBoundedTree(2, grbtree.EvictMax, nil)
+	for _, k := range []int{5, 1, 9, 7, 3} {
+		bt.Add(k, k)
+	}
+	if max, _, _ := bt.GetMax(); max != 3 || bt.Len() != 2 {
+		t.Fatalf("GetMax %v", max)
+	}
+}
+
+func TestBoundedTreeNegativeCapacity(t *testing.T) {
+	evicted := 0
+	bt := grbtree.NewBoundedTree(-1, grbtree.EvictMin, func(k grbtree.RBTreeKey, v interface{}) {
+		evicted++
+	})
+	bt.Add(1, 1)
+	if bt.Capacity() != 0 || bt.Len() != 0 || evicted != 1 {
+		t.Fatalf("Capacity %d, Len %d, evicted %d", bt.Capacity(), bt.Len(), evicted)
+	}
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestCOWTree(t *testing.T) {
+	tree := grbtree.NewCOWTree()
+	for i := 0; i < 100; i++ {
+		tree.Add(i, i)
+	}
+	for i := 0; i < 100; i += 2 {
+		tree.Del(i)
+	}
+	if tree.Len() != 50 {
+		t.Fatalf("Len %d", tree.Len())
+	}
+	if k, _, _ := tree.GetMin(); k != 1 {
+		t.Fatalf("GetMin %v", k)
+	}
+	if k, _, _ := tree.GetMax(); k != 99 {
+		t.Fatalf("GetMax %v", k)
+	}
+	var ks []grbtree.RBTreeKey
+	tree.Range(10, 20, func(k grbtree.RBTreeKey, v interface{}) bool {
+		ks = append(ks, k)
+		return true
+	})
+	if len(ks) != 5 || ks[0] != 11 || ks[4] != 19 {
+		t.Fatalf("Range %v", ks)
+	}
+}
+
+func TestCOWTreeSnapshotRange(t *testing.T) {
+	tree := grbtree.NewCOWTree()
+	for i := 0; i < 10; i++ {
+		tree.Add(i, i)
+	}
+	count := 0
+	tree.Range(0, 9, func(k grbtree.RBTreeKey, v interface{}) bool {
+		// 遍历时的修改不影响正在遍历的版本
+		tree.Del(int(k) + 1)
+		count++
+		return true
+	})
+	if count != 10 || tree.Len() != 1 {
+		t.Fatalf("count %d Len %d", count, tree.Len())
+	}
+}
+
+func TestCOWTreeConcurrent(t *testing.T) {
+	tree := grbtree.NewCOWTree()
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			tree.Add(i, i)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			if v, err := tree.Get(i / 2); err == nil && v.(int) != i/2 {
+				t.Error("Get wrong value")
+				return
+			}
+			tree.GetMax()
+		}
+	}()
+	wg.Wait()
+	if tree.Len() != 1000 {
+		t.Fatalf("Len %d", tree.Len())
+	}
+}
+
+func TestCOWTreeOptions(t *testing.T) {
+	desc := func(a, b grbtree.RBTreeKey) int {
+		if a > b {
+			return -1
+		} else if a < b {
+			return 1
+		}
+		return 0
+	}
+	tree := grbtree.NewCOWTree(grbtree.WithCOWComparator(desc), grbtree.WithCOWReplace(true))
+	for i := 0; i < 10; i++ {
+		tree.Add(i, i)
+	}
+	tree.Add(5, "x")
+	if k, _, _ := tree.GetMin(); k != 9 {
+		t.Fatalf("GetMin %v", k)
+	}
+	if v, _ := tree.Get(5); v != "x" || tree.Len() != 10 {
+		t.Fatalf("Get %v", v)
+	}
+	tree = grbtree.NewCOWTree()
+	tree.Add(5, 1)
+	tree.Add(5, 2)
+	if v, _ := tree.Get(5); v != 1 {
+		t.Fatalf("Get %v", v)
+	}
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestCursor(t *testing.T) {
+	tree := grbtree.NewRBTree()
+	treeAddTestKs(tree, []int{55, 38, 80, 25, 46, 76, 72})
+	var ks []grbtree.RBTreeKey
+	for c := tree.Cursor(); c.Next(); {
+		ks = append(ks, c.Key())
+	}
+	want := []grbtree.RBTreeKey{25, 38, 46, 55, 72, 76, 80}
+	if len(ks) != len(want) {
+		t.Fatalf("Next %v", ks)
+	}
+	for i := range want {
+		if ks[i] != want[i] {
+			t.Fatalf("Next %v", ks)
+		}
+	}
+
+	c := tree.Cursor()
+	if !c.Seek(50) || c.Key() != 55 || !c.Prev() || c.Key() != 46 {
+		t.Fatal("Seek/Prev error")
+	}
+	c = tree.Cursor()
+	if !c.Prev() || c.Key() != 80 {
+		t.Fatal("Prev from start error")
+	}
+}
+
+func TestCursorConcurrentModification(t *testing.T) {
+	tree := grbtree.NewRBTree()
+	treeAddTestKs(tree, []int{1, 2, 3, 4})
+	c := tree.Cursor()
+	c.Next()
+	c.SetValue(10)
+	if !c.Next() || c.Err() != nil {
+		t.Fatal("SetValue should not invalidate cursor")
+	}
+	tree.Add(5, 1)
+	if c.Next() || c.Err() == nil {
+		t.Fatal("modification not detected")
+	}
+	if c.Valid() || c.Key() != 0 {
+		t.Fatal("cursor still valid")
+	}
+	if !c.First() || c.Err() != nil {
+		t.Fatal("First should reset cursor")
+	}
+	tree.Del(3)
+	if c.Valid() || c.Err() == nil {
+		t.Fatal("Del not detected")
+	}
+}
+
+func TestCursorDelete(t *testing.T) {
+	tree := grbtree.NewRBTree(grbtree.WithValidation(true))
+	for i := 0; i < 200; i++ {
+		tree.Add(i, i)
+	}
+	var seen []grbtree.RBTreeKey
+	for c := tree.Cursor(); c.Next(); {
+		seen = append(seen, c.Key())
+		if c.Key()%3 != 0 && !c.Delete() {
+			t.Fatal("Delete failed")
+		}
+	}
+	if len(seen) != 200 {
+		t.Fatalf("visited %d nodes", len(seen))
+	}
+	for i, k := range seen {
+		if int(k) != i {
+			t.Fatalf("visited %v", seen)
+		}
+	}
+	if tree.Len() != 67 {
+		t.Fatalf("Len %d", tree.Len())
+	}
+
+	c := tree.Cursor()
+	c.Seek(30)
+	c.Delete()
+	if !c.Prev() || c.Key() != 27 {
+		t.Fatal("Prev after Delete error")
+	}
+}
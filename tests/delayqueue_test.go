@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestDelayQueue(t *testing.T) {
+	q := grbtree.NewDelayQueue()
+	now := time.Now()
+	q.Put(now.Add(60*time.Millisecond), "b")
+	q.Put(now.Add(-time.Millisecond), "a")
+	if q.Len() != 2 {
+		t.Fatalf("Len %d", q.Len())
+	}
+	ctx := context.Background()
+	for _, want := range []string{"a", "b"} {
+		v, err := q.Take(ctx)
+		if err != nil || v.(string) != want {
+			t.Fatalf("Take %v %v", v, err)
+		}
+	}
+	if time.Since(now) < 60*time.Millisecond {
+		t.Fatal("Take returned before deadline")
+	}
+}
+
+func TestDelayQueueWakeEarly(t *testing.T) {
+	q := grbtree.NewDelayQueue()
+	q.Put(time.Now().Add(time.Hour), "late")
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		q.Put(time.Now().Add(10*time.Millisecond), "early")
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	v, err := q.Take(ctx)
+	if err != nil || v.(string) != "early" {
+		t.Fatalf("Take %v %v", v, err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := q.Take(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Take err %v", err)
+	}
+}
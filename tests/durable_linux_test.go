@@ -0,0 +1,55 @@
+//go:build linux
+
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+// 用 RLIMIT_FSIZE 限制文件大小，使写入日志时只写入部分记录后返回 EFBIG
+func TestDurableTreeShortWrite(t *testing.T) {
+	dir := t.TempDir()
+	dt, err := grbtree.OpenDurableTree(dir, grbtree.WithSnapshotEvery(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dt.Add(1, "a")
+	info, _ := os.Stat(filepath.Join(dir, "wal"))
+
+	var old syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_FSIZE, &old); err != nil {
+		t.Skip(err)
+	}
+	limit := old
+	limit.Cur = uint64(info.Size()) + 10
+	if err := syscall.Setrlimit(syscall.RLIMIT_FSIZE, &limit); err != nil {
+		t.Skip(err)
+	}
+	err = dt.Add(2, strings.Repeat("x", 100))
+	syscall.Setrlimit(syscall.RLIMIT_FSIZE, &old)
+	if err == nil {
+		t.Fatal("short write not reported")
+	}
+	if v, err := dt.Get(2); err == nil {
+		t.Fatalf("failed Add applied: %v", v)
+	}
+	if err := dt.Add(3, "c"); err != nil {
+		t.Fatal(err)
+	}
+	dt.Close()
+
+	dt, err = grbtree.OpenDurableTree(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dt.Close()
+	if _, err := dt.Get(3); err != nil || dt.Len() != 2 {
+		t.Fatalf("Len %d, Get(3) %v", dt.Len(), err)
+	}
+}
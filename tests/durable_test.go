@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestDurableTree(t *testing.T) {
+	dir := t.TempDir()
+	dt, err := grbtree.OpenDurableTree(dir, grbtree.WithSnapshotEvery(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		if err := dt.Add(i, i*10); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 20; i += 3 {
+		if err := dt.Del(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dt.Close()
+
+	dt, err = grbtree.OpenDurableTree(dir, grbtree.WithSnapshotEvery(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dt.Close()
+	if dt.Len() != 13 {
+		t.Fatalf("Len %d", dt.Len())
+	}
+	for i := 0; i < 20; i++ {
+		v, err := dt.Get(i)
+		if i%3 == 0 {
+			if err == nil {
+				t.Fatalf("Get deleted key %d", i)
+			}
+		} else if err != nil || v.(int) != i*10 {
+			t.Fatalf("Get %d: %v %v", i, v, err)
+		}
+	}
+}
+
+func TestDurableTreeTornRecord(t *testing.T) {
+	dir := t.TempDir()
+	dt, err := grbtree.OpenDurableTree(dir, grbtree.WithSnapshotEvery(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dt.Add(1, "a")
+	dt.Add(2, "b")
+	dt.Close()
+
+	// 模拟写入最后一条记录时崩溃
+	wal := filepath.Join(dir, "wal")
+	info, _ := os.Stat(wal)
+	if err := os.Truncate(wal, info.Size()-3); err != nil {
+		t.Fatal(err)
+	}
+	dt, err = grbtree.OpenDurableTree(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dt.Len() != 1 {
+		t.Fatalf("Len %d", dt.Len())
+	}
+	dt.Add(3, "c")
+	dt.Close()
+
+	dt, err = grbtree.OpenDurableTree(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dt.Close()
+	if v, err := dt.Get(3); err != nil || v.(string) != "c" || dt.Len() != 2 {
+		t.Fatalf("Get after recovery %v %v", v, err)
+	}
+}
+
+func TestDurableTreeCorruptRecord(t *testing.T) {
+	dir := t.TempDir()
+	dt, err := grbtree.OpenDurableTree(dir, grbtree.WithSnapshotEvery(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		dt.Add(i, i)
+	}
+	dt.Close()
+
+	// 损坏第一条记录的最后一个字节，后面的记录是完整的，不能当作不完整的记录截掉
+	wal := filepath.Join(dir, "wal")
+	data, _ := os.ReadFile(wal)
+	data[len(data)/5-1] ^= 0xff
+	os.WriteFile(wal, data, 0o644)
+	if _, err := grbtree.OpenDurableTree(dir); err == nil {
+		t.Fatal("corrupt record not reported")
+	}
+	if info, _ := os.Stat(wal); info.Size() != int64(len(data)) {
+		t.Fatalf("wal truncated to %d", info.Size())
+	}
+}
+
+func TestDurableTreeSnapshotBeforeTruncate(t *testing.T) {
+	dir := t.TempDir()
+	opts := []grbtree.DurableOption{
+		grbtree.WithSnapshotEvery(0),
+		grbtree.WithTreeOptions(grbtree.WithDuplicatePolicy(grbtree.DuplicateAllow)),
+	}
+	dt, err := grbtree.OpenDurableTree(dir, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		dt.Add(1, i)
+	}
+	wal := filepath.Join(dir, "wal")
+	data, _ := os.ReadFile(wal)
+	if err := dt.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	dt.Close()
+
+	// 模拟写入快照后、清空日志前崩溃
+	os.WriteFile(wal, data, 0o644)
+	dt, err = grbtree.OpenDurableTree(dir, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dt.Len() != 5 {
+		t.Fatalf("Len %d after replay", dt.Len())
+	}
+	dt.Add(1, 5)
+	dt.Close()
+
+	dt, err = grbtree.OpenDurableTree(dir, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dt.Close()
+	if dt.Len() != 6 {
+		t.Fatalf("Len %d after reopen", dt.Len())
+	}
+}
+
+func TestDurableTreeUnwrittenTail(t *testing.T) {
+	dir := t.TempDir()
+	dt, err := grbtree.OpenDurableTree(dir, grbtree.WithSnapshotEvery(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dt.Add(1, "a")
+	dt.Add(2, "b")
+	dt.Close()
+
+	// 模拟最后一条记录的空间已分配但数据没有写入，长度完整但校验失败
+	wal := filepath.Join(dir, "wal")
+	data, _ := os.ReadFile(wal)
+	size := len(data)
+	data = append(data, 0, 0, 0, 5, 1, 2, 3, 4, 0, 0, 0, 0, 0)
+	os.WriteFile(wal, data, 0o644)
+	dt, err = grbtree.OpenDurableTree(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dt.Close()
+	if dt.Len() != 2 {
+		t.Fatalf("Len %d", dt.Len())
+	}
+	if info, _ := os.Stat(wal); info.Size() != int64(size) {
+		t.Fatalf("wal size %d, want %d", info.Size(), size)
+	}
+}
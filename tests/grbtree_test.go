@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/chr193997060/grbtree"
+	"github.com/chr193997060/grbtree/v2"
 )
 
 func findMinMax(t []int) ([2]int, error) {
@@ -36,7 +36,7 @@ func treeAddTestKs(t *grbtree.RBTree, ks []int){
 func addCase1(t *testing.T, tree *grbtree.RBTree){
 	addks := []int{55, 38, 80, 25, 46, 76, 72,  }
 	treeAddTestKs(tree, addks)
-	if int(tree.Len) != len(addks) {
+	if int(tree.Len()) != len(addks) {
 		t.Fail()
 	}
 	min_max, _ := findMinMax(addks)
@@ -92,3 +92,22 @@ func TestDel(t *testing.T){
 	delCase1(t, rbt)
 	delCase2(t, rbt)
 }
+
+func TestNodeAccessor(t *testing.T){
+	tree := grbtree.NewRBTree()
+	treeAddTestKs(tree, []int{55, 38, 80})
+	root := tree.Root()
+	if root.Key() != 55 || root.IsRed() {
+		t.Fatal("root error")
+	}
+	if !root.GetLeft().IsRed() || root.GetLeft().Key() != 38 {
+		t.Fatal("left error")
+	}
+	root.SetValue("x")
+	if v, _ := tree.Get(55); v.(string) != "x" || root.Value().(string) != "x" {
+		t.Fatal("SetValue error")
+	}
+	if tree.Len() != 3 {
+		t.Fatal("Len error")
+	}
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestIDAllocator(t *testing.T) {
+	a := grbtree.NewIDAllocator(1, 100)
+	for i := int64(1); i <= 10; i++ {
+		if id, err := a.Allocate(); err != nil || id != i {
+			t.Fatalf("Allocate %v %v", id, err)
+		}
+	}
+	if a.RangeCount() != 1 {
+		t.Fatalf("RangeCount %d", a.RangeCount())
+	}
+	a.Release(3)
+	a.Release(4)
+	a.Release(8)
+	if err := a.Release(8); err == nil {
+		t.Fatal("Release free ID")
+	}
+	if id, _ := a.Allocate(); id != 3 {
+		t.Fatalf("Allocate %d", id)
+	}
+	// 空闲 4, 8, 11~100
+	if id, _ := a.AllocateRange(2); id != 11 {
+		t.Fatalf("AllocateRange %d", id)
+	}
+	want := []grbtree.Range{{Lo: 4, Hi: 4}, {Lo: 8, Hi: 8}, {Lo: 13, Hi: 20}}
+	if got := a.FreeRanges(2, 20); !reflect.DeepEqual(got, want) {
+		t.Fatalf("FreeRanges %v", got)
+	}
+	a.Allocate()
+	a.Allocate()
+	if !a.IsAllocated(8) || a.RangeCount() != 1 {
+		t.Fatalf("ranges not coalesced: %d", a.RangeCount())
+	}
+	if _, err := a.AllocateRange(100); err == nil {
+		t.Fatal("AllocateRange larger than free space")
+	}
+	if id, err := a.AllocateRange(88); err != nil || id != 13 {
+		t.Fatalf("AllocateRange %v %v", id, err)
+	}
+	if _, err := a.Allocate(); err == nil {
+		t.Fatal("Allocate when full")
+	}
+}
+
+func TestIDAllocatorFreeRangesClamped(t *testing.T) {
+	a := grbtree.NewIDAllocator(1, 10)
+	a.Allocate()
+	want := []grbtree.Range{{Lo: 2, Hi: 10}}
+	if got := a.FreeRanges(-5, 100); !reflect.DeepEqual(got, want) {
+		t.Fatalf("FreeRanges %v", got)
+	}
+	if got := a.FreeRanges(20, 30); len(got) != 0 {
+		t.Fatalf("FreeRanges outside [min, max] %v", got)
+	}
+}
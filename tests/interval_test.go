@@ -0,0 +1,37 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestIntervalSet(t *testing.T) {
+	s := grbtree.NewIntervalSet()
+	s.Add(0, 99)
+	s.Add(200, 299)
+	s.Add(100, 149) // 与 [0, 99] 相邻
+	s.Add(250, 400) // 与 [200, 299] 重叠
+	want := []grbtree.Range{{Lo: 0, Hi: 149}, {Lo: 200, Hi: 400}}
+	if got := s.Ranges(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Ranges %v", got)
+	}
+	if !s.Contains(149) || s.Contains(150) || !s.Covers(210, 400) || s.Covers(100, 210) {
+		t.Fatal("Contains/Covers error")
+	}
+	want = []grbtree.Range{{Lo: 150, Hi: 199}, {Lo: 401, Hi: 500}}
+	if got := s.Gaps(100, 500); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Gaps %v", got)
+	}
+
+	s.Remove(50, 59)
+	s.Remove(140, 260)
+	want = []grbtree.Range{{Lo: 0, Hi: 49}, {Lo: 60, Hi: 139}, {Lo: 261, Hi: 400}}
+	if got := s.Ranges(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Ranges after Remove %v", got)
+	}
+	if s.Len() != 3 {
+		t.Fatalf("Len %d", s.Len())
+	}
+}
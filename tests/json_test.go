@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	tree := grbtree.NewRBTree()
+	tree.Add(3, "c")
+	tree.Add(1, "a")
+	tree.Add(2, "b")
+	data, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"key":1,"value":"a"},{"key":2,"value":"b"},{"key":3,"value":"c"}]`
+	if string(data) != want {
+		t.Fatalf("MarshalJSON %s", data)
+	}
+	data, err = tree.MarshalJSONStructure()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = `{"key":2,"value":"b","color":"black",` +
+		`"left":{"key":1,"value":"a","color":"red","left":null,"right":null},` +
+		`"right":{"key":3,"value":"c","color":"red","left":null,"right":null}}`
+	if string(data) != want {
+		t.Fatalf("MarshalJSONStructure %s", data)
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	for _, data := range []string{
+		`[{"key":1,"value":"a"},{"key":2,"value":"b"},{"key":3,"value":"c"}]`,
+		`[{"key":3,"value":"c"},{"key":1,"value":"a"},{"key":2,"value":"b"}]`,
+	} {
+		tree := grbtree.NewRBTree()
+		if err := json.Unmarshal([]byte(data), tree); err != nil {
+			t.Fatal(err)
+		}
+		if err := tree.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		if v, _ := tree.Get(2); tree.Len() != 3 || v.(string) != "b" {
+			t.Fatalf("Unmarshal %s", data)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestOrderedLRU(t *testing.T) {
+	var evicted []grbtree.RBTreeKey
+	c := grbtree.NewOrderedLRU(3, func(k grbtree.RBTreeKey, v interface{}) {
+		evicted = append(evicted, k)
+	})
+	c.Add(3, "c")
+	c.Add(1, "a")
+	c.Add(2, "b")
+	if _, err := c.Get(3); err != nil {
+		t.Fatal(err)
+	}
+	c.Add(4, "d") // 淘汰最久未访问的 1
+	c.Add(2, "b2")
+	c.Add(5, "e") // 淘汰 3
+	if _, err := c.Get(1); err == nil {
+		t.Fatal("evicted key returned")
+	}
+	if len(evicted) != 2 || evicted[0] != 1 || evicted[1] != 3 {
+		t.Fatalf("evicted %v", evicted)
+	}
+
+	var ks []grbtree.RBTreeKey
+	c.Range(0, 4, func(k grbtree.RBTreeKey, v interface{}) bool {
+		ks = append(ks, k)
+		return true
+	})
+	if len(ks) != 2 || ks[0] != 2 || ks[1] != 4 {
+		t.Fatalf("Range %v", ks)
+	}
+	if v, _ := c.Peek(2); v.(string) != "b2" {
+		t.Fatalf("Peek %v", v)
+	}
+	s := c.Stats()
+	if s.Hits != 1 || s.Misses != 1 || s.Evictions != 2 || c.Len() != 3 {
+		t.Fatalf("Stats %+v", s)
+	}
+}
+
+func TestOrderedLRUNegativeCapacity(t *testing.T) {
+	c := grbtree.NewOrderedLRU(-1, nil)
+	c.Add(1, 1)
+	if _, err := c.Get(1); err == nil || c.Len() != 0 {
+		t.Fatalf("Len %d", c.Len())
+	}
+}
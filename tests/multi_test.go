@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestMultiAdd(t *testing.T) {
+	tree := grbtree.NewMultiRBTree()
+	for i, k := range []int{30, 10, 20, 10, 30, 10} {
+		tree.Add(k, i)
+	}
+	if tree.Len() != 6 {
+		t.Fatalf("len %d", tree.Len())
+	}
+	if tree.Count(10) != 3 || tree.Count(20) != 1 || tree.Count(40) != 0 {
+		t.Fatal("count error")
+	}
+	vs := tree.GetAll(10)
+	if len(vs) != 3 || vs[0].(int) != 1 || vs[1].(int) != 3 || vs[2].(int) != 5 {
+		t.Fatalf("GetAll order error: %v", vs)
+	}
+	v, err := tree.Get(30)
+	if err != nil || v.(int) != 0 {
+		t.Fatalf("Get %v %v", v, err)
+	}
+	_, v, _ = tree.GetMax()
+	if v.(int) != 4 {
+		t.Fatalf("max value %v", v)
+	}
+}
+
+func TestMultiDel(t *testing.T) {
+	tree := grbtree.NewMultiRBTree()
+	for i, k := range []int{10, 10, 10, 20} {
+		tree.Add(k, i)
+	}
+	if !tree.DeleteOne(10, func(v interface{}) bool { return v.(int) == 1 }) {
+		t.Fatal("DeleteOne not found")
+	}
+	if tree.DeleteOne(10, func(v interface{}) bool { return v.(int) == 1 }) {
+		t.Fatal("DeleteOne deleted twice")
+	}
+	vs := tree.GetAll(10)
+	if len(vs) != 2 || vs[0].(int) != 0 || vs[1].(int) != 2 {
+		t.Fatalf("GetAll %v", vs)
+	}
+	tree.Del(10)
+	if tree.Count(10) != 0 || tree.Len() != 1 {
+		t.Fatal("Del error")
+	}
+}
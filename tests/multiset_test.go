@@ -0,0 +1,44 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestMultiSet(t *testing.T) {
+	s := grbtree.NewMultiSet()
+	s.Add(10, 3)
+	s.Add(5, 1)
+	s.Add(20, 2)
+	s.Add(10, 1)
+	if s.Count(10) != 4 || s.Size() != 7 || s.Len() != 3 {
+		t.Fatal("Add error")
+	}
+	if s.Remove(20, 5) != 2 || s.Count(20) != 0 || s.Len() != 2 {
+		t.Fatal("Remove error")
+	}
+	if s.Remove(10, 1) != 1 || s.Count(10) != 3 {
+		t.Fatal("Remove error")
+	}
+	if s.Rank(10) != 1 || s.Rank(11) != 4 {
+		t.Fatal("Rank error")
+	}
+}
+
+func TestMultiSetSelect(t *testing.T) {
+	s := grbtree.NewMultiSet()
+	s.Add(1, 2)
+	s.Add(3, 1)
+	s.Add(2, 3)
+	want := []grbtree.RBTreeKey{1, 1, 2, 2, 2, 3}
+	for i, k := range want {
+		got, err := s.Select(uint64(i))
+		if err != nil || got != k {
+			t.Fatalf("Select(%d) = %v, %v", i, got, err)
+		}
+	}
+	if _, err := s.Select(uint64(len(want))); err == nil {
+		t.Fatal("Select out of range")
+	}
+}
@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestNodeHandle(t *testing.T) {
+	tree := grbtree.NewRBTree(grbtree.WithValidation(true))
+	treeAddTestKs(tree, []int{55, 38, 80, 25, 46, 76, 72})
+	handles := map[int]*grbtree.RBTreeNode{}
+	for _, k := range []int{25, 38, 46, 72, 76, 80} {
+		handles[k] = tree.GetNode(k)
+	}
+	// 55 有两个子节点，删除后其后继节点 72 的指针仍然有效
+	tree.Del(55)
+	for k, n := range handles {
+		if int(n.Key()) != k || tree.GetNode(k) != n {
+			t.Fatalf("handle %d changed to %v", k, n.Key())
+		}
+	}
+	n := handles[46]
+	if n.Next().Key() != 72 || n.Prev().Key() != 38 {
+		t.Fatal("Next/Prev error")
+	}
+	if tree.MinNode().Prev() != nil || tree.MaxNode().Next() != nil {
+		t.Fatal("Next/Prev at the end error")
+	}
+	if !tree.DeleteNode(n) || tree.DeleteNode(n) {
+		t.Fatal("DeleteNode error")
+	}
+	if n.Next() != nil || tree.Len() != 5 {
+		t.Fatal("deleted node still linked")
+	}
+}
+
+func TestDeleteNodeForeignHandle(t *testing.T) {
+	tree := grbtree.NewRBTree()
+	treeAddTestKs(tree, []int{5, 3, 8})
+	n := tree.GetNode(5)
+	tree.Clear()
+	tree.Add(100, 1)
+	if tree.DeleteNode(n) {
+		t.Fatal("deleted a node from before Clear")
+	}
+	other := grbtree.NewRBTree()
+	treeAddTestKs(other, []int{1, 2, 3})
+	if tree.DeleteNode(other.GetNode(2)) {
+		t.Fatal("deleted a node of another tree")
+	}
+	if err := tree.Validate(); err != nil || tree.Len() != 1 {
+		t.Fatal(err)
+	}
+	if err := other.Validate(); err != nil || other.Len() != 3 {
+		t.Fatal(err)
+	}
+}
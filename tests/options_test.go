@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestOptionComparator(t *testing.T) {
+	desc := func(a, b grbtree.RBTreeKey) int {
+		if a > b {
+			return -1
+		} else if a < b {
+			return 1
+		}
+		return 0
+	}
+	tree := grbtree.NewRBTree(grbtree.WithComparator(desc), grbtree.WithValidation(true))
+	treeAddTestKs(tree, []int{55, 38, 80, 25, 46, 76, 72})
+	first, _, _ := tree.GetMin()
+	last, _, _ := tree.GetMax()
+	if first != 80 || last != 25 {
+		t.Fatalf("GetMin %v GetMax %v", first, last)
+	}
+	tree.Del(80)
+	if first, _, _ = tree.GetMin(); first != 76 {
+		t.Fatalf("GetMin %v", first)
+	}
+}
+
+func TestOptionDuplicatePolicy(t *testing.T) {
+	tree := grbtree.NewRBTree()
+	tree.Add(1, "a")
+	tree.Add(1, "b")
+	if v, _ := tree.Get(1); v.(string) != "a" {
+		t.Fatal("DuplicateIgnore error")
+	}
+
+	tree = grbtree.NewRBTree(grbtree.WithDuplicatePolicy(grbtree.DuplicateReplace))
+	tree.Add(1, "a")
+	tree.Add(1, "b")
+	if v, _ := tree.Get(1); v.(string) != "b" || tree.Len() != 1 {
+		t.Fatal("DuplicateReplace error")
+	}
+
+	tree = grbtree.NewRBTree(grbtree.WithDuplicatePolicy(grbtree.DuplicateAllow))
+	tree.Add(1, "a")
+	tree.Add(1, "b")
+	if tree.Count(1) != 2 {
+		t.Fatal("DuplicateAllow error")
+	}
+}
+
+func TestOptionCapacity(t *testing.T) {
+	tree := grbtree.NewRBTree(grbtree.WithCapacity(4), grbtree.WithValidation(true))
+	for i := 0; i < 10; i++ {
+		tree.Add(i, i)
+	}
+	for i := 0; i < 10; i += 2 {
+		tree.Del(i)
+	}
+	if tree.Len() != 5 {
+		t.Fatalf("Len %d", tree.Len())
+	}
+	if err := tree.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOptionComparatorEqualKeys(t *testing.T) {
+	// 按 key/10 分组，11 和 15 比较时相等
+	bucket := func(a, b grbtree.RBTreeKey) int {
+		if a/10 < b/10 {
+			return -1
+		} else if a/10 > b/10 {
+			return 1
+		}
+		return 0
+	}
+	for _, p := range []grbtree.DuplicatePolicy{grbtree.DuplicateIgnore, grbtree.DuplicateAllow} {
+		tree := grbtree.NewRBTree(grbtree.WithComparator(bucket), grbtree.WithDuplicatePolicy(p))
+		tree.Add(11, "a")
+		if v, err := tree.Get(15); err != nil || v.(string) != "a" {
+			t.Fatalf("policy %d: Get %v %v", p, v, err)
+		}
+		if tree.Count(15) != 1 || len(tree.GetAll(15)) != 1 {
+			t.Fatalf("policy %d: Count %d", p, tree.Count(15))
+		}
+		if !tree.DeleteOne(15, nil) || tree.Len() != 0 {
+			t.Fatalf("policy %d: DeleteOne error", p)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2/orderbook"
+)
+
+func TestOrderBook(t *testing.T) {
+	b := orderbook.New()
+	b.Add(1, orderbook.Bid, 99, 10)
+	b.Add(2, orderbook.Bid, 100, 5)
+	b.Add(3, orderbook.Bid, 100, 7)
+	b.Add(4, orderbook.Ask, 102, 4)
+	b.Add(5, orderbook.Ask, 101, 6)
+	if err := b.Add(5, orderbook.Ask, 101, 6); err == nil {
+		t.Fatal("duplicate order id")
+	}
+	if p, q, _ := b.BestBid(); p != 100 || q != 12 {
+		t.Fatalf("BestBid %d %d", p, q)
+	}
+	if p, q, _ := b.BestAsk(); p != 101 || q != 6 {
+		t.Fatalf("BestAsk %d %d", p, q)
+	}
+
+	// 卖 15 @ 99: 先成交 100 档的 2、3，再成交 99 档的 1
+	fills, rest := b.Match(orderbook.Ask, 99, 15)
+	want := []orderbook.Fill{{MakerID: 2, Price: 100, Qty: 5}, {MakerID: 3, Price: 100, Qty: 7}, {MakerID: 1, Price: 99, Qty: 3}}
+	if rest != 0 || !reflect.DeepEqual(fills, want) {
+		t.Fatalf("Match %v %d", fills, rest)
+	}
+	if o, _ := b.Order(1); o.Qty != 7 {
+		t.Fatalf("order 1 Qty %d", o.Qty)
+	}
+
+	// 买 20 @ 101: 只能成交 101 档
+	fills, rest = b.Match(orderbook.Bid, 101, 20)
+	if len(fills) != 1 || fills[0].MakerID != 5 || rest != 14 {
+		t.Fatalf("Match %v %d", fills, rest)
+	}
+
+	b.Add(6, orderbook.Ask, 103, 1)
+	if err := b.Cancel(4); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Cancel(4); err == nil {
+		t.Fatal("Cancel twice")
+	}
+	bids, asks := b.Depth(5)
+	if !reflect.DeepEqual(bids, []orderbook.Level{{Price: 99, Qty: 7, Orders: 1}}) ||
+		!reflect.DeepEqual(asks, []orderbook.Level{{Price: 103, Qty: 1, Orders: 1}}) {
+		t.Fatalf("Depth %v %v", bids, asks)
+	}
+}
@@ -3,7 +3,7 @@ package tests
 import (
 	"testing"
 
-	"github.com/chr193997060/grbtree"
+	"github.com/chr193997060/grbtree/v2"
 )
 
 
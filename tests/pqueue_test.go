@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	q := grbtree.NewPriorityQueue()
+	a := q.Push(5, "a")
+	b := q.Push(3, "b")
+	q.Push(3, "c")
+	d := q.Push(7, "d")
+	q.Push(1, "e")
+	if !q.Update(a, 3) || !q.Remove(d) || q.Remove(d) {
+		t.Fatal("Update/Remove error")
+	}
+	if b.Priority() != 3 || a.Priority() != 3 {
+		t.Fatal("Priority error")
+	}
+	var got string
+	for q.Len() > 0 {
+		it, err := q.PopMin()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got += it.Value().(string)
+	}
+	// 优先级相同时按插入顺序，a 修改优先级后排在 b、c 之后
+	if got != "ebca" {
+		t.Fatalf("PopMin order %s", got)
+	}
+	if _, err := q.PopMin(); err == nil {
+		t.Fatal("PopMin on empty queue")
+	}
+	if q.Update(a, 1) {
+		t.Fatal("Update popped item")
+	}
+}
+
+func TestPriorityQueueForeignItem(t *testing.T) {
+	a, b := grbtree.NewPriorityQueue(), grbtree.NewPriorityQueue()
+	var it *grbtree.PQItem
+	for i := 0; i < 5; i++ {
+		it = a.Push(i, i)
+		b.Push(i, i)
+	}
+	if b.Remove(it) || b.Update(it, 10) {
+		t.Fatal("accepted an item of another queue")
+	}
+	if a.Len() != 5 || b.Len() != 5 || it.Priority() != 4 {
+		t.Fatal("queues changed")
+	}
+	if !a.Remove(it) || a.Len() != 4 {
+		t.Fatal("Remove error")
+	}
+}
@@ -0,0 +1,32 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestWindowQuantile(t *testing.T) {
+	wq := grbtree.NewWindowQuantile(100)
+	if _, err := wq.Quantile(0.5); err == nil {
+		t.Fatal("Quantile on empty window")
+	}
+	for i := int64(1); i <= 100; i++ {
+		wq.Push(i)
+	}
+	for q, want := range map[float64]int64{0: 1, 0.5: 50, 0.95: 95, 1: 100} {
+		if got, err := wq.Quantile(q); err != nil || got != want {
+			t.Fatalf("Quantile(%v) = %v, %v", q, got, err)
+		}
+	}
+	// 窗口满后最早的样本 1~50 被删除
+	for i := 0; i < 50; i++ {
+		wq.Push(1000)
+	}
+	if got, _ := wq.Quantile(0); got != 51 || wq.Len() != 100 {
+		t.Fatalf("min %v Len %d", got, wq.Len())
+	}
+	if got, _ := wq.Quantile(0.51); got != 1000 {
+		t.Fatalf("p51 %v", got)
+	}
+}
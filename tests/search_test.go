@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestFloorCeiling(t *testing.T) {
+	tree := grbtree.NewRBTree()
+	treeAddTestKs(tree, []int{10, 20, 30})
+	if k, _, err := tree.Floor(25); err != nil || k != 20 {
+		t.Fatalf("Floor %v %v", k, err)
+	}
+	if k, _, err := tree.Ceiling(25); err != nil || k != 30 {
+		t.Fatalf("Ceiling %v %v", k, err)
+	}
+	if k, _, _ := tree.Ceiling(20); k != 20 {
+		t.Fatalf("Ceiling %v", k)
+	}
+	if _, _, err := tree.Floor(5); err == nil {
+		t.Fatal("Floor below min")
+	}
+	if _, _, err := tree.Ceiling(31); err == nil {
+		t.Fatal("Ceiling above max")
+	}
+}
+
+func TestNearest(t *testing.T) {
+	tree := grbtree.NewRBTree()
+	if _, _, err := tree.Nearest(1); err == nil {
+		t.Fatal("Nearest on empty tree")
+	}
+	treeAddTestKs(tree, []int{10, 20, 30, 45, 60})
+	for k, want := range map[int]grbtree.RBTreeKey{-5: 10, 14: 10, 15: 10, 16: 20, 30: 30, 100: 60} {
+		if got, _, err := tree.Nearest(k); err != nil || got != want {
+			t.Fatalf("Nearest(%d) = %v, %v", k, got, err)
+		}
+	}
+	ns := tree.KNearest(32, 4)
+	want := []grbtree.RBTreeKey{30, 20, 45, 10}
+	if len(ns) != len(want) {
+		t.Fatalf("KNearest len %d", len(ns))
+	}
+	for i, n := range ns {
+		if n.Key() != want[i] {
+			t.Fatalf("KNearest[%d] = %v", i, n.Key())
+		}
+	}
+	if len(tree.KNearest(0, 10)) != 5 {
+		t.Fatal("KNearest more than Len")
+	}
+}
@@ -0,0 +1,39 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestSequence(t *testing.T) {
+	s := grbtree.NewSequence[string]("a", "b", "c")
+	s.InsertAt(0, "x")
+	s.InsertAt(2, "y")
+	s.InsertAt(5, "z")
+	if got, _ := s.Slice(0, s.Len()); !reflect.DeepEqual(got, []string{"x", "a", "y", "b", "c", "z"}) {
+		t.Fatalf("Slice %v", got)
+	}
+	if err := s.InsertAt(7, "w"); err == nil {
+		t.Fatal("InsertAt out of range")
+	}
+	if v, _ := s.DeleteAt(1); v != "a" {
+		t.Fatalf("DeleteAt %v", v)
+	}
+	s.Set(0, "X")
+	if v, _ := s.At(0); v != "X" {
+		t.Fatalf("At %v", v)
+	}
+	if got, _ := s.Slice(1, 3); !reflect.DeepEqual(got, []string{"y", "b"}) {
+		t.Fatalf("Slice %v", got)
+	}
+	if _, err := s.At(s.Len()); err == nil {
+		t.Fatal("At out of range")
+	}
+
+	s.Concat(grbtree.NewSequence[string]("m", "n"))
+	if got, _ := s.Slice(0, s.Len()); !reflect.DeepEqual(got, []string{"X", "y", "b", "c", "z", "m", "n"}) {
+		t.Fatalf("Concat %v", got)
+	}
+}
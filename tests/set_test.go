@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestSet(t *testing.T) {
+	s := grbtree.NewSet[int](5, 1, 3)
+	if !s.Insert(2) || s.Insert(3) {
+		t.Fatal("Insert error")
+	}
+	if !s.Contains(2) || s.Contains(4) {
+		t.Fatal("Contains error")
+	}
+	if !s.Remove(5) || s.Remove(5) {
+		t.Fatal("Remove error")
+	}
+	if !reflect.DeepEqual(s.Keys(), []int{1, 2, 3}) {
+		t.Fatalf("Keys %v", s.Keys())
+	}
+	min, _ := s.Min()
+	max, _ := s.Max()
+	if min != 1 || max != 3 || s.Len() != 3 {
+		t.Fatal("Min/Max/Len error")
+	}
+}
+
+func TestSetOperation(t *testing.T) {
+	a := grbtree.NewSet[int32](1, 2, 3, 4)
+	b := grbtree.NewSet[int32](3, 4, 5)
+	if !reflect.DeepEqual(a.Union(b).Keys(), []int32{1, 2, 3, 4, 5}) {
+		t.Fatal("Union error")
+	}
+	if !reflect.DeepEqual(a.Intersect(b).Keys(), []int32{3, 4}) {
+		t.Fatal("Intersect error")
+	}
+	if !reflect.DeepEqual(a.Difference(b).Keys(), []int32{1, 2}) {
+		t.Fatal("Difference error")
+	}
+}
+
+func TestSetOperationLarge(t *testing.T) {
+	a, b := grbtree.NewSet[int](), grbtree.NewSet[int]()
+	for i := 0; i < 1000; i++ {
+		a.Insert(i * 2)
+		b.Insert(i * 3)
+	}
+	for _, s := range []*grbtree.Set[int]{a.Union(b), a.Intersect(b), a.Difference(b)} {
+		ks := s.Keys()
+		for i := 1; i < len(ks); i++ {
+			if ks[i-1] >= ks[i] {
+				t.Fatal("keys not sorted")
+			}
+		}
+		for _, k := range ks {
+			if !s.Contains(k) {
+				t.Fatalf("Contains %d error", k)
+			}
+		}
+	}
+	if a.Union(b).Len() != 1666 || a.Intersect(b).Len() != 334 || a.Difference(b).Len() != 666 {
+		t.Fatal("Len error")
+	}
+}
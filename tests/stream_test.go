@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	tree := grbtree.NewRBTree()
+	for i := 0; i < 1000; i++ {
+		tree.Add(i*37%1009, i)
+	}
+	for _, opts := range [][]grbtree.EncodeOption{nil, {grbtree.WithGzip(gzip.BestSpeed)}} {
+		var buf bytes.Buffer
+		if err := tree.Encode(&buf, opts...); err != nil {
+			t.Fatal(err)
+		}
+		loaded := grbtree.NewRBTree()
+		if err := loaded.Decode(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if err := loaded.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		if loaded.Len() != tree.Len() {
+			t.Fatalf("Len %d", loaded.Len())
+		}
+		for i := 0; i < 1000; i++ {
+			if v, err := loaded.Get(i * 37 % 1009); err != nil || v.(int) != i {
+				t.Fatalf("Get %d: %v %v", i, v, err)
+			}
+		}
+	}
+}
+
+func TestDecodeCorrupt(t *testing.T) {
+	tree := grbtree.NewRBTree()
+	for i := 0; i < 10; i++ {
+		tree.Add(i, i)
+	}
+	var buf bytes.Buffer
+	if err := tree.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	data[len(data)-1] ^= 0xff
+	loaded := grbtree.NewRBTree()
+	loaded.Add(100, 1)
+	if err := loaded.Decode(bytes.NewReader(data)); err == nil {
+		t.Fatal("checksum not verified")
+	}
+	if v, err := loaded.Get(100); err != nil || v.(int) != 1 || loaded.Len() != 1 {
+		t.Fatal("tree changed on error")
+	}
+	if err := loaded.Decode(bytes.NewReader(data[:len(data)/2])); err == nil {
+		t.Fatal("truncated data decoded")
+	}
+}
+
+func TestDecodeConcatenated(t *testing.T) {
+	a, b := grbtree.NewRBTree(), grbtree.NewRBTree()
+	for i := 0; i < 100; i++ {
+		a.Add(i, i)
+		b.Add(i+1000, i)
+	}
+	for _, opts := range [][]grbtree.EncodeOption{nil, {grbtree.WithGzip(gzip.BestSpeed)}} {
+		var buf bytes.Buffer
+		if err := a.Encode(&buf, opts...); err != nil {
+			t.Fatal(err)
+		}
+		if err := b.Encode(&buf, opts...); err != nil {
+			t.Fatal(err)
+		}
+		buf.WriteString("tail")
+		for _, want := range []*grbtree.RBTree{a, b} {
+			loaded := grbtree.NewRBTree()
+			if err := loaded.Decode(&buf); err != nil {
+				t.Fatal(err)
+			}
+			wk, _, _ := want.GetMin()
+			if k, _, _ := loaded.GetMin(); loaded.Len() != want.Len() || k != wk {
+				t.Fatalf("Len %d, Min %d", loaded.Len(), k)
+			}
+		}
+		if buf.String() != "tail" {
+			t.Fatalf("remaining %q", buf.String())
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chr193997060/grbtree/v2"
+)
+
+func TestTTLTree(t *testing.T) {
+	var evicted []grbtree.RBTreeKey
+	tt := grbtree.NewTTLTree(func(k grbtree.RBTreeKey, v interface{}) {
+		evicted = append(evicted, k)
+	})
+	tt.SetWithTTL(1, "a", -time.Second)
+	tt.SetWithTTL(2, "b", -time.Second)
+	tt.SetWithTTL(3, "c", time.Hour)
+	tt.Set(4, "d")
+	// 重新设置后不再过期
+	tt.Set(2, "b2")
+
+	if _, err := tt.Get(1); err == nil {
+		t.Fatal("expired key returned")
+	}
+	if v, err := tt.Get(2); err != nil || v.(string) != "b2" {
+		t.Fatalf("Get %v %v", v, err)
+	}
+	if len(evicted) != 1 || evicted[0] != 1 {
+		t.Fatalf("evicted %v", evicted)
+	}
+	tt.SetWithTTL(5, "e", -time.Second)
+	tt.SetWithTTL(3, "c", -time.Second)
+	if n := tt.Sweep(); n != 2 || tt.Len() != 2 {
+		t.Fatalf("Sweep %d Len %d", n, tt.Len())
+	}
+	if len(evicted) != 3 || evicted[1] != 5 || evicted[2] != 3 {
+		t.Fatalf("evicted %v", evicted)
+	}
+}
+
+func TestTTLTreeSweeper(t *testing.T) {
+	var mu sync.Mutex
+	count := 0
+	tt := grbtree.NewTTLTree(func(k grbtree.RBTreeKey, v interface{}) {
+		mu.Lock()
+		count++
+		mu.Unlock()
+	})
+	for i := 0; i < 10; i++ {
+		tt.SetWithTTL(i, i, 10*time.Millisecond)
+	}
+	stop := tt.StartSweeper(5 * time.Millisecond)
+	defer stop()
+	// Sweep 在解锁后才调用 onEvict，Len 为 0 时回调可能还没有全部执行，因此等待 count
+	evictedCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return count
+	}
+	deadline := time.Now().Add(time.Second)
+	for evictedCount() < 10 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if c := evictedCount(); tt.Len() != 0 || c != 10 {
+		t.Fatalf("Len %d evicted %d", tt.Len(), c)
+	}
+}
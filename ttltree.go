@@ -0,0 +1,152 @@
+package grbtree
+
+import (
+	"sync"
+	"time"
+)
+
+// TTLTree 中的值
+type ttlEntry struct {
+	value  interface{}
+	expire *RBTreeNode // 过期索引中的节点，nil 表示不会过期
+}
+
+// 带过期时间的树，可以被多个 goroutine 同时使用
+// 除了保存数据的树外，还有一个按过期时间排序的索引树(key 为过期时间的 UnixNano，值为数据的 key)，
+// Get 时发现过期的节点会被删除，Sweep 通过索引树的 PopMin 删除所有过期的节点
+//
+//	tt := grbtree.NewTTLTree(func(k grbtree.RBTreeKey, v interface{}) { ... })
+//	tt.SetWithTTL(1, "a", time.Minute)
+//	stop := tt.StartSweeper(time.Second)
+//	defer stop()
+type TTLTree struct {
+	mu      sync.Mutex
+	t       *RBTree
+	expiry  *RBTree
+	onEvict func(k RBTreeKey, v interface{})
+}
+
+// onEvict 在节点过期被删除时调用，可以为 nil
+func NewTTLTree(onEvict func(k RBTreeKey, v interface{})) *TTLTree {
+	return &TTLTree{
+		t:       NewRBTree(),
+		expiry:  NewMultiRBTree(),
+		onEvict: onEvict,
+	}
+}
+
+// 设置不会过期的值
+func (tt *TTLTree) Set(k int, v interface{}) {
+	tt.set(RBTreeKey(k), v, time.Time{})
+}
+
+// 设置 ttl 后过期的值，已存在的 key 将替换值并重新设置过期时间
+func (tt *TTLTree) SetWithTTL(k int, v interface{}, ttl time.Duration) {
+	tt.set(RBTreeKey(k), v, time.Now().Add(ttl))
+}
+
+func (tt *TTLTree) set(k RBTreeKey, v interface{}, expireAt time.Time) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	e := &ttlEntry{value: v}
+	if n := tt.t.findNode(k); n != nil {
+		tt.expiry.DeleteNode(n.value.(*ttlEntry).expire)
+		n.value = e
+	} else {
+		tt.t.add(k, e)
+	}
+	if !expireAt.IsZero() {
+		e.expire = tt.expiry.newNode(RBTreeKey(expireAt.UnixNano()), k)
+		tt.expiry.addNode(e.expire)
+	}
+}
+
+// 获取值，已过期的值视为不存在并被删除
+func (tt *TTLTree) Get(k int) (interface{}, error) {
+	tt.mu.Lock()
+	n := tt.t.findNode(RBTreeKey(k))
+	if n == nil {
+		tt.mu.Unlock()
+		return nil, errKeyNotExists
+	}
+	e := n.value.(*ttlEntry)
+	if e.expire == nil || e.expire.key > RBTreeKey(time.Now().UnixNano()) {
+		tt.mu.Unlock()
+		return e.value, nil
+	}
+	tt.expiry.DeleteNode(e.expire)
+	tt.t.delete(n)
+	tt.mu.Unlock()
+	if tt.onEvict != nil {
+		tt.onEvict(RBTreeKey(k), e.value)
+	}
+	return nil, errKeyNotExists
+}
+
+// 删除节点，不会调用 onEvict
+func (tt *TTLTree) Del(k int) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	n := tt.t.findNode(RBTreeKey(k))
+	if n == nil {
+		return
+	}
+	tt.expiry.DeleteNode(n.value.(*ttlEntry).expire)
+	tt.t.delete(n)
+}
+
+// 节点数量，包括已过期但还未被删除的节点
+func (tt *TTLTree) Len() int {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	return tt.t.Len()
+}
+
+// 删除所有已过期的节点，返回删除的数量
+func (tt *TTLTree) Sweep() int {
+	type evicted struct {
+		k RBTreeKey
+		v interface{}
+	}
+	var es []evicted
+	now := RBTreeKey(time.Now().UnixNano())
+	tt.mu.Lock()
+	for {
+		expireAt, _, err := tt.expiry.GetMin()
+		if err != nil || expireAt > now {
+			break
+		}
+		_, k, _ := tt.expiry.PopMin()
+		n := tt.t.findNode(k.(RBTreeKey))
+		es = append(es, evicted{k: n.key, v: n.value.(*ttlEntry).value})
+		tt.t.delete(n)
+	}
+	tt.mu.Unlock()
+	if tt.onEvict != nil {
+		for _, e := range es {
+			tt.onEvict(e.k, e.v)
+		}
+	}
+	return len(es)
+}
+
+// 启动 goroutine 每隔 interval 调用一次 Sweep，返回的函数用于停止
+func (tt *TTLTree) StartSweeper(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				tt.Sweep()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
@@ -0,0 +1,94 @@
+package grbtree
+
+import "fmt"
+
+// 检查红黑树的性质：
+// 根节点为黑色、红色节点的子节点为黑色、每条路径的黑色节点数量相同、
+// 节点按 key 有序、父子节点指针一致、节点数量和最大\最小节点正确
+func (t *RBTree) Validate() error {
+	if t.root == nil {
+		if t.length != 0 || t.minNode != nil || t.maxNode != nil {
+			return fmt.Errorf("grbtree: empty tree has Len %d", t.length)
+		}
+		return nil
+	}
+	if t.root.parent != nil {
+		return fmt.Errorf("grbtree: root has parent")
+	}
+	if !t.root.isBlack() {
+		return fmt.Errorf("grbtree: root is red")
+	}
+	var count uint32
+	if _, err := t.validateNode(t.root, &count); err != nil {
+		return err
+	}
+	if count != t.length {
+		return fmt.Errorf("grbtree: Len is %d, but tree has %d nodes", t.length, count)
+	}
+	min, max := t.root, t.root
+	for min.left != nil {
+		min = min.left
+	}
+	for max.right != nil {
+		max = max.right
+	}
+	if t.minNode != min || t.maxNode != max {
+		return fmt.Errorf("grbtree: min/max node is not cached correctly")
+	}
+	return nil
+}
+
+// 检查以 n 为根的子树，返回子树的黑色高度
+func (t *RBTree) validateNode(n *RBTreeNode, count *uint32) (int, error) {
+	if n == nil {
+		return 1, nil
+	}
+	*count++
+	for _, c := range []*RBTreeNode{n.left, n.right} {
+		if c == nil {
+			continue
+		}
+		if c.parent != n {
+			return 0, fmt.Errorf("grbtree: node %v has wrong parent", c.key)
+		}
+		if !n.isBlack() && !c.isBlack() {
+			return 0, fmt.Errorf("grbtree: red node %v has red child %v", n.key, c.key)
+		}
+	}
+	if n.left != nil && t.compare(n.left.key, n.key) > 0 || n.right != nil && t.compare(n.right.key, n.key) < 0 {
+		return 0, fmt.Errorf("grbtree: node %v is out of order", n.key)
+	}
+	if n.size <= n.left.subSize()+n.right.subSize() {
+		return 0, fmt.Errorf("grbtree: node %v has wrong size", n.key)
+	}
+	if t.dup != DuplicateAllow {
+		if n.left != nil && t.compare(n.left.key, n.key) == 0 || n.right != nil && t.compare(n.right.key, n.key) == 0 {
+			return 0, fmt.Errorf("grbtree: duplicate key %v", n.key)
+		}
+	}
+	lh, err := t.validateNode(n.left, count)
+	if err != nil {
+		return 0, err
+	}
+	rh, err := t.validateNode(n.right, count)
+	if err != nil {
+		return 0, err
+	}
+	if lh != rh {
+		return 0, fmt.Errorf("grbtree: node %v has different black height", n.key)
+	}
+	if n.isBlack() {
+		lh++
+	}
+	return lh, nil
+}
+
+// 开启检查时，树不满足红黑树性质则 panic
+func (t *RBTree) checkValid() {
+	if !t.validation {
+		return
+	}
+	if err := t.Validate(); err != nil {
+		panic(err)
+	}
+}